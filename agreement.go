@@ -0,0 +1,259 @@
+package haai
+
+import "math"
+
+// ratingScale is the set of values a rated field can take. Values of an
+// ordinal scale are in order; ordinal fields use quadratic weights in Cohen's
+// kappa and the ordinal metric in Krippendorff's alpha.
+type ratingScale struct {
+	values  []string
+	ordinal bool
+}
+
+func (s ratingScale) index(v string) int {
+	for i, x := range s.values {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// ratingMatrix holds coded ratings as [unit][rater] scale indexes, -1 for missing
+type ratingMatrix [][]int
+
+// complete returns the units rated by every rater
+func (m ratingMatrix) complete() ratingMatrix {
+	var out ratingMatrix
+	for _, u := range m {
+		ok := true
+		for _, v := range u {
+			if v < 0 {
+				ok = false
+			}
+		}
+		if ok {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// pairable returns each unit's present values, for units with at least two
+func (m ratingMatrix) pairable() [][]int {
+	var out [][]int
+	for _, u := range m {
+		var vals []int
+		for _, v := range u {
+			if v >= 0 {
+				vals = append(vals, v)
+			}
+		}
+		if len(vals) >= 2 {
+			out = append(out, vals)
+		}
+	}
+	return out
+}
+
+// percentAgreement is the share of rater pairs that gave identical values
+func percentAgreement(m ratingMatrix) float64 {
+	agree, pairs := 0, 0
+	for _, vals := range m.pairable() {
+		for i := range vals {
+			for j := i + 1; j < len(vals); j++ {
+				pairs++
+				if vals[i] == vals[j] {
+					agree++
+				}
+			}
+		}
+	}
+	if pairs == 0 {
+		return math.NaN()
+	}
+	return float64(agree) / float64(pairs)
+}
+
+// cohensKappa computes Cohen's kappa for the first two raters over units both
+// rated, with quadratic agreement weights on ordinal scales
+func cohensKappa(m ratingMatrix, s ratingScale) float64 {
+	k := len(s.values)
+	if k == 0 {
+		return math.NaN()
+	}
+	p := make([][]float64, k)
+	for i := range p {
+		p[i] = make([]float64, k)
+	}
+	n := 0
+	for _, u := range m {
+		if len(u) < 2 || u[0] < 0 || u[1] < 0 {
+			continue
+		}
+		p[u[0]][u[1]]++
+		n++
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	rows := make([]float64, k)
+	cols := make([]float64, k)
+	for i := range p {
+		for j := range p[i] {
+			p[i][j] /= float64(n)
+			rows[i] += p[i][j]
+			cols[j] += p[i][j]
+		}
+	}
+	weight := func(i, j int) float64 {
+		if !s.ordinal || k == 1 {
+			if i == j {
+				return 1
+			}
+			return 0
+		}
+		d := float64(i-j) / float64(k-1)
+		return 1 - d*d
+	}
+	po, pe := 0.0, 0.0
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			po += weight(i, j) * p[i][j]
+			pe += weight(i, j) * rows[i] * cols[j]
+		}
+	}
+	return chanceCorrected(po, pe)
+}
+
+// fleissKappa computes Fleiss' kappa over the units rated by every rater
+func fleissKappa(m ratingMatrix, s ratingScale) float64 {
+	units := m.complete()
+	if len(units) == 0 || len(units[0]) < 2 {
+		return math.NaN()
+	}
+	raters := float64(len(units[0]))
+	totals := make([]float64, len(s.values))
+	po := 0.0
+	for _, u := range units {
+		counts := make([]float64, len(s.values))
+		for _, v := range u {
+			counts[v]++
+			totals[v]++
+		}
+		agree := 0.0
+		for _, c := range counts {
+			agree += c * (c - 1)
+		}
+		po += agree / (raters * (raters - 1))
+	}
+	po /= float64(len(units))
+	pe := 0.0
+	for _, t := range totals {
+		share := t / (raters * float64(len(units)))
+		pe += share * share
+	}
+	return chanceCorrected(po, pe)
+}
+
+// krippendorffAlpha computes Krippendorff's alpha with the nominal or ordinal
+// metric; units with fewer than two ratings are ignored
+func krippendorffAlpha(m ratingMatrix, s ratingScale) float64 {
+	k := len(s.values)
+	o := make([][]float64, k)
+	for i := range o {
+		o[i] = make([]float64, k)
+	}
+	for _, vals := range m.pairable() {
+		w := 1 / float64(len(vals)-1)
+		for i := range vals {
+			for j := range vals {
+				if i != j {
+					o[vals[i]][vals[j]] += w
+				}
+			}
+		}
+	}
+	nc := make([]float64, k)
+	n := 0.0
+	for c := range o {
+		for _, x := range o[c] {
+			nc[c] += x
+		}
+		n += nc[c]
+	}
+	if n <= 1 {
+		return math.NaN()
+	}
+
+	delta := func(c, d int) float64 {
+		if c == d {
+			return 0
+		}
+		if !s.ordinal {
+			return 1
+		}
+		if c > d {
+			c, d = d, c
+		}
+		sum := 0.0
+		for g := c; g <= d; g++ {
+			sum += nc[g]
+		}
+		sum -= (nc[c] + nc[d]) / 2
+		return sum * sum
+	}
+	observed, expected := 0.0, 0.0
+	for c := 0; c < k; c++ {
+		for d := 0; d < k; d++ {
+			observed += o[c][d] * delta(c, d)
+			expected += nc[c] * nc[d] * delta(c, d)
+		}
+	}
+	observed /= n
+	expected /= n * (n - 1)
+	if expected == 0 {
+		if observed == 0 {
+			return 1
+		}
+		return math.NaN()
+	}
+	return 1 - observed/expected
+}
+
+// chanceCorrected returns (po - pe) / (1 - pe), or 1 when both are 1 (all
+// raters used one value and agreed) and NaN when undefined
+func chanceCorrected(po, pe float64) float64 {
+	if pe >= 1 {
+		if po >= 1 {
+			return 1
+		}
+		return math.NaN()
+	}
+	return (po - pe) / (1 - pe)
+}
+
+// unitDisagreement is the mean distance between a unit's rater pairs, from 0
+// (full agreement) to 1 (opposite ends of the scale, or differing nominal values)
+func unitDisagreement(vals []int, s ratingScale) float64 {
+	sum, pairs := 0.0, 0
+	for i := range vals {
+		for j := i + 1; j < len(vals); j++ {
+			if vals[i] < 0 || vals[j] < 0 {
+				continue
+			}
+			pairs++
+			switch {
+			case vals[i] == vals[j]:
+			case s.ordinal && len(s.values) > 1:
+				sum += math.Abs(float64(vals[i]-vals[j])) / float64(len(s.values)-1)
+			default:
+				sum++
+			}
+		}
+	}
+	if pairs == 0 {
+		return 0
+	}
+	return sum / float64(pairs)
+}
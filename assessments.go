@@ -0,0 +1,355 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultCapabilityLevels is used when scoring.json does not list aiCapability values
+var defaultCapabilityLevels = []string{"solved", "near_solved", "partial", "early", "not_attempted"}
+
+// CapabilityLevels returns the allowed aiCapability values from most to least capable.
+func (ds *Dataset) CapabilityLevels() []string {
+	if vals := ds.AttributeValues("aiCapability"); len(vals) > 0 {
+		return vals
+	}
+	return defaultCapabilityLevels
+}
+
+// AttributeValues returns the allowed values of a categorical attribute in scoring.json.
+func (ds *Dataset) AttributeValues(shortName string) []string {
+	for _, attr := range ds.scoring.Attributes {
+		if attr.ShortName != shortName {
+			continue
+		}
+		vals := make([]string, len(attr.Values))
+		for i, v := range attr.Values {
+			vals[i] = v.Value
+		}
+		return vals
+	}
+	return nil
+}
+
+// capabilityRank orders capability levels so that a higher rank is more capable
+func (ds *Dataset) capabilityRank(level string) int {
+	levels := ds.CapabilityLevels()
+	for i, l := range levels {
+		if l == level {
+			return len(levels) - i
+		}
+	}
+	return 0
+}
+
+// AssessmentDates lists the dates (file names without .json) of every
+// assessment snapshot in assessments/, oldest first.
+func (ds *Dataset) AssessmentDates() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(ds.dir, "assessments"))
+	if err != nil {
+		return nil, err
+	}
+	var dates []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			dates = append(dates, strings.TrimSuffix(entry.Name(), ".json"))
+		}
+	}
+	sort.Strings(dates)
+	return dates, nil
+}
+
+// LoadAssessment reads the assessment snapshot for the given date (YYYY-MM-DD).
+func (ds *Dataset) LoadAssessment(date string) (*AssessmentFile, error) {
+	date = strings.TrimSuffix(filepath.Base(date), ".json")
+	var af AssessmentFile
+	if err := ds.loadJSON("assessments/"+date+".json", &af); err != nil {
+		return nil, err
+	}
+	if af.AssessmentDate == "" {
+		af.AssessmentDate = date
+	}
+	return &af, nil
+}
+
+// Entries decodes every per-activity assessment, skipping non-activity keys
+// such as "description".
+func (af *AssessmentFile) Entries() map[string]ActivityAssessment {
+	entries := make(map[string]ActivityAssessment)
+	for id, raw := range af.ActivityAssessments {
+		var aa ActivityAssessment
+		if err := json.Unmarshal(raw, &aa); err == nil {
+			entries[id] = aa
+		}
+	}
+	return entries
+}
+
+// AssessmentDiff describes what moved between two assessment snapshots.
+type AssessmentDiff struct {
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	Changes      []ActivityChange `json:"changes"`
+	Added        []string         `json:"added,omitempty"`
+	Removed      []string         `json:"removed,omitempty"`
+	Domains      []DomainShift    `json:"domains"`
+	Capabilities []LevelShift     `json:"capabilities"`
+	Timelines    []FieldChange    `json:"timelines,omitempty"`
+	Weights      []FieldChange    `json:"weights,omitempty"`
+}
+
+// ActivityChange is one activity whose aiCapability, bottleneck or agiWave changed.
+type ActivityChange struct {
+	ID       string             `json:"id"`
+	Name     string             `json:"name"`
+	DomainID int                `json:"domainId"`
+	From     ActivityAssessment `json:"from"`
+	To       ActivityAssessment `json:"to"`
+	Fields   []string           `json:"fields"`
+}
+
+// DomainShift aggregates the changes within one domain.
+type DomainShift struct {
+	DomainID       int     `json:"domainId"`
+	Name           string  `json:"name"`
+	Activities     int     `json:"activities"`
+	Changed        int     `json:"changed"`
+	CapabilityUp   int     `json:"capabilityUp"`
+	CapabilityDown int     `json:"capabilityDown"`
+	MeanWaveFrom   float64 `json:"meanWaveFrom"`
+	MeanWaveTo     float64 `json:"meanWaveTo"`
+}
+
+// LevelShift is the number of activities at one capability level in each snapshot.
+type LevelShift struct {
+	Level string `json:"level"`
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+}
+
+// FieldChange is a changed scalar in agiWaveTimelines or compositeWeights.
+type FieldChange struct {
+	Path string `json:"path"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DiffAssessments compares two snapshots. Activity names and domains are
+// taken from the dataset.
+func (ds *Dataset) DiffAssessments(from, to *AssessmentFile) *AssessmentDiff {
+	d := &AssessmentDiff{From: from.AssessmentDate, To: to.AssessmentDate}
+	a, b := from.Entries(), to.Entries()
+
+	names := make(map[string]string)
+	for _, act := range ds.activities {
+		names[act.ID] = act.Name
+	}
+
+	domains := make(map[int]*DomainShift)
+	domainOf := func(id string) *DomainShift {
+		n := DomainFromID(id)
+		if domains[n] == nil {
+			shift := &DomainShift{DomainID: n}
+			if dom, ok := ds.Domain(n); ok {
+				shift.Name = dom.Name
+			}
+			domains[n] = shift
+		}
+		return domains[n]
+	}
+
+	fromCount := make(map[string]int)
+	toCount := make(map[string]int)
+	waveSums := make(map[int][2]float64)
+	waveCounts := make(map[int][2]int)
+
+	for _, id := range sortedActivityIDs(a, b) {
+		x, inA := a[id]
+		y, inB := b[id]
+		shift := domainOf(id)
+		sums, counts := waveSums[shift.DomainID], waveCounts[shift.DomainID]
+		if inA {
+			fromCount[x.AICapability]++
+			sums[0] += float64(x.AGIWave)
+			counts[0]++
+		}
+		if inB {
+			toCount[y.AICapability]++
+			sums[1] += float64(y.AGIWave)
+			counts[1]++
+		}
+		waveSums[shift.DomainID], waveCounts[shift.DomainID] = sums, counts
+
+		switch {
+		case !inA:
+			d.Added = append(d.Added, id)
+			continue
+		case !inB:
+			d.Removed = append(d.Removed, id)
+			continue
+		}
+		shift.Activities++
+
+		var fields []string
+		if x.AICapability != y.AICapability {
+			fields = append(fields, "aiCapability")
+			if ds.capabilityRank(y.AICapability) > ds.capabilityRank(x.AICapability) {
+				shift.CapabilityUp++
+			} else {
+				shift.CapabilityDown++
+			}
+		}
+		if x.Bottleneck != y.Bottleneck {
+			fields = append(fields, "bottleneck")
+		}
+		if x.AGIWave != y.AGIWave {
+			fields = append(fields, "agiWave")
+		}
+		if len(fields) > 0 {
+			shift.Changed++
+			d.Changes = append(d.Changes, ActivityChange{
+				ID: id, Name: names[id], DomainID: shift.DomainID,
+				From: x, To: y, Fields: fields,
+			})
+		}
+	}
+
+	for n, shift := range domains {
+		if c := waveCounts[n]; c[0] > 0 {
+			shift.MeanWaveFrom = waveSums[n][0] / float64(c[0])
+		}
+		if c := waveCounts[n]; c[1] > 0 {
+			shift.MeanWaveTo = waveSums[n][1] / float64(c[1])
+		}
+		d.Domains = append(d.Domains, *shift)
+	}
+	sort.Slice(d.Domains, func(i, j int) bool { return d.Domains[i].DomainID < d.Domains[j].DomainID })
+
+	for _, level := range ds.CapabilityLevels() {
+		d.Capabilities = append(d.Capabilities, LevelShift{Level: level, From: fromCount[level], To: toCount[level]})
+	}
+
+	d.Timelines = diffFlattened(flattenJSON("", from.AGIWaveTimelines), flattenJSON("", to.AGIWaveTimelines))
+	d.Weights = diffFlattened(flattenJSON("", from.CompositeWeights), flattenJSON("", to.CompositeWeights))
+
+	return d
+}
+
+// sortedActivityIDs returns the union of keys in activity ID order
+func sortedActivityIDs(maps ...map[string]ActivityAssessment) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, m := range maps {
+		for id := range m {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return CompareIDs(ids[i], ids[j]) < 0 })
+	return ids
+}
+
+// CompareIDs orders dotted IDs numerically ("2.10" after "2.9").
+func CompareIDs(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		if errA != nil || errB != nil {
+			if c := strings.Compare(pa[i], pb[i]); c != 0 {
+				return c
+			}
+			continue
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(pa) - len(pb)
+}
+
+// flattenJSON turns a value into path -> scalar string pairs. Arrays of
+// objects with a "wave" key are addressed by wave number rather than index
+// so reordering does not show up as a change.
+func flattenJSON(prefix string, v any) map[string]string {
+	out := make(map[string]string)
+	data, err := json.Marshal(v)
+	if err != nil {
+		return out
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return out
+	}
+	flattenInto(out, prefix, generic)
+	return out
+}
+
+func flattenInto(out map[string]string, prefix string, v any) {
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+	switch x := v.(type) {
+	case map[string]any:
+		for k, child := range x {
+			flattenInto(out, join(k), child)
+		}
+	case []any:
+		scalars := true
+		for _, child := range x {
+			if _, ok := child.(map[string]any); ok {
+				scalars = false
+			}
+		}
+		if scalars {
+			parts := make([]string, len(x))
+			for i, child := range x {
+				parts[i] = fmt.Sprint(child)
+			}
+			out[prefix] = strings.Join(parts, ", ")
+			return
+		}
+		for i, child := range x {
+			key := fmt.Sprint(i)
+			if m, ok := child.(map[string]any); ok {
+				if w, ok := m["wave"]; ok {
+					key = fmt.Sprintf("wave%v", w)
+				}
+			}
+			flattenInto(out, join(key), child)
+		}
+	case nil:
+	default:
+		out[prefix] = fmt.Sprint(x)
+	}
+}
+
+func diffFlattened(a, b map[string]string) []FieldChange {
+	var changes []FieldChange
+	keys := make(map[string]bool)
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	for _, k := range sortedKeys(keys) {
+		if a[k] != b[k] {
+			changes = append(changes, FieldChange{Path: k, From: a[k], To: b[k]})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,195 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// How an ATUS category's minutes were split over its HAAI references
+const (
+	SplitBreakdown = "breakdown"
+	SplitWeights   = "weights"
+	SplitEven      = "even"
+)
+
+// ATUSWeights holds user weights per ATUS code: for each code, the weight of
+// each HAAI category or domain ID. A code with weights is split by exactly
+// those weights instead of its breakdown or mappings.json weights.
+type ATUSWeights map[string]map[HAAIRef]float64
+
+// ReadATUSWeights reads ATUS weights from a JSON file of the form
+// {"08": {"5": 0.5, "6.3": 0.4, "6.4": 0.1}}.
+func ReadATUSWeights(path string) (ATUSWeights, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]map[string]float64
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	w := make(ATUSWeights)
+	for code, refs := range raw {
+		code = strings.TrimSpace(code)
+		w[code] = make(map[HAAIRef]float64)
+		for ref, weight := range refs {
+			w[code][HAAIRef(strings.TrimSpace(ref))] = weight
+		}
+	}
+	return w, nil
+}
+
+// ATUSAllocation is the average ATUS day allocated to HAAI activities. Each
+// ATUS category's minutes are split over its HAAI references, then evenly
+// over the activities under each reference, so every activity, category and
+// domain gets an estimated number of minutes per day.
+type ATUSAllocation struct {
+	TotalMinutes float64               `json:"totalMinutes"`
+	Unallocated  float64               `json:"unallocatedMinutes"` // minutes on references without activities
+	Entries      []ATUSEntryAllocation `json:"entries"`
+	Activities   map[string]float64    `json:"activities"` // minutes per activity ID
+	Categories   map[string]float64    `json:"categories"` // minutes per category ID
+	Domains      map[int]float64       `json:"domains"`    // minutes per domain ID
+}
+
+// ATUSEntryAllocation is how one ATUS category's minutes were allocated.
+type ATUSEntryAllocation struct {
+	ATUSCode     string              `json:"atusCode"`
+	ATUSCategory string              `json:"atusCategory"`
+	Minutes      float64             `json:"minutes"`
+	Split        string              `json:"split"`      // breakdown, weights or even
+	Refs         map[HAAIRef]float64 `json:"refs"`       // minutes per HAAI reference
+	Activities   map[string]float64  `json:"activities"` // minutes per activity ID
+}
+
+// AllocateATUS allocates the ATUS average day to HAAI activities. A category
+// with a breakdown is split by the minutes of each breakdown item, whose
+// references come from breakdownRefs; items without references, and
+// categories without a breakdown, are split by haaiWeights or, without
+// those, evenly over haaiCategories. weights may be nil.
+func (ds *Dataset) AllocateATUS(weights ATUSWeights) (*ATUSAllocation, error) {
+	out := &ATUSAllocation{
+		Activities: make(map[string]float64),
+		Categories: make(map[string]float64),
+		Domains:    make(map[int]float64),
+	}
+	for _, e := range ds.mappings.ATUSMapping.Mappings {
+		ea, err := ds.allocateATUSEntry(e, weights[e.ATUSCode])
+		if err != nil {
+			return nil, fmt.Errorf("ATUS %s: %w", e.ATUSCode, err)
+		}
+		out.TotalMinutes += ea.Minutes
+		allocated := 0.0
+		for id, m := range ea.Activities {
+			a, _ := ds.Activity(id)
+			out.Activities[id] += m
+			out.Categories[a.CategoryID] += m
+			out.Domains[DomainFromID(id)] += m
+			allocated += m
+		}
+		if rest := ea.Minutes - allocated; rest > 1e-9 {
+			out.Unallocated += rest
+		}
+		out.Entries = append(out.Entries, *ea)
+	}
+	return out, nil
+}
+
+func (ds *Dataset) allocateATUSEntry(e ATUSEntry, weights map[HAAIRef]float64) (*ATUSEntryAllocation, error) {
+	ea := &ATUSEntryAllocation{
+		ATUSCode:     e.ATUSCode,
+		ATUSCategory: e.ATUSCategory,
+		Minutes:      float64(e.AvgMinutesPerDay),
+		Refs:         make(map[HAAIRef]float64),
+		Activities:   make(map[string]float64),
+	}
+
+	var breakdownTotal int
+	for _, m := range e.Breakdown {
+		breakdownTotal += m
+	}
+	switch {
+	case len(weights) > 0:
+		ea.Split = SplitWeights
+		if err := splitByWeights(ea.Refs, ea.Minutes, weights); err != nil {
+			return nil, err
+		}
+	case len(e.BreakdownRefs) > 0 && breakdownTotal > 0:
+		// Breakdown items are scaled so they add up to avgMinutesPerDay
+		ea.Split = SplitBreakdown
+		scale := ea.Minutes / float64(breakdownTotal)
+		for _, item := range sortedKeys(e.Breakdown) {
+			m := float64(e.Breakdown[item]) * scale
+			refs := e.BreakdownRefs[item]
+			if len(refs) == 0 {
+				if err := splitByWeights(ea.Refs, m, e.refWeights()); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			for _, r := range refs {
+				ea.Refs[r] += m / float64(len(refs))
+			}
+		}
+	default:
+		ea.Split = SplitEven
+		if len(e.HAAIWeights) > 0 {
+			ea.Split = SplitWeights
+		}
+		if err := splitByWeights(ea.Refs, ea.Minutes, e.refWeights()); err != nil {
+			return nil, err
+		}
+	}
+
+	refs := make([]HAAIRef, 0, len(ea.Refs))
+	for r := range ea.Refs {
+		refs = append(refs, r)
+	}
+	sort.Slice(refs, func(i, j int) bool { return CompareIDs(string(refs[i]), string(refs[j])) < 0 })
+	for _, r := range refs {
+		acts, err := ds.ResolveRef(r)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range acts {
+			ea.Activities[a.ID] += ea.Refs[r] / float64(len(acts))
+		}
+	}
+	return ea, nil
+}
+
+// refWeights returns the entry's haaiWeights, or equal weights for its
+// haaiCategories
+func (e ATUSEntry) refWeights() map[HAAIRef]float64 {
+	if len(e.HAAIWeights) > 0 {
+		return e.HAAIWeights
+	}
+	w := make(map[HAAIRef]float64)
+	for _, c := range e.HAICategories {
+		w[HAAIRef(c)] = 1
+	}
+	return w
+}
+
+// splitByWeights adds minutes to refs in proportion to weights
+func splitByWeights(refs map[HAAIRef]float64, minutes float64, weights map[HAAIRef]float64) error {
+	var total float64
+	for r, w := range weights {
+		if w < 0 {
+			return fmt.Errorf("weight for %s is negative", r)
+		}
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("no weighted HAAI references")
+	}
+	for r, w := range weights {
+		if w > 0 {
+			refs[r] += minutes * w / total
+		}
+	}
+	return nil
+}
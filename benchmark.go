@@ -0,0 +1,166 @@
+package haai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Benchmarks with a crosswalk in mappings.json
+const (
+	BenchmarkBehavior1K  = "behavior1k"
+	BenchmarkActivityNet = "activitynet"
+)
+
+// Benchmarks returns the names accepted by BenchmarkCoverage.
+func Benchmarks() []string {
+	return []string{BenchmarkBehavior1K, BenchmarkActivityNet}
+}
+
+// firstPhysicalDomain is the first of the embodied domains (7-10)
+const firstPhysicalDomain = 7
+
+// BenchmarkCategory is one benchmark task category or action type with the
+// HAAI activities it measures.
+type BenchmarkCategory struct {
+	Name       string         `json:"name"`
+	Examples   []string       `json:"examples"`
+	Refs       []HAAIRef      `json:"haaiRefs"`
+	Activities []Activity     `json:"activities"`
+	Capability map[string]int `json:"capability"` // activities per aiCapability level
+	Bottleneck string         `json:"bottleneck"` // most common bottleneck
+}
+
+// PhysicalCategoryCoverage records which benchmarks measure a category in the
+// physical domains. A category with no benchmarks is a measurement gap.
+type PhysicalCategoryCoverage struct {
+	Category   Category `json:"category"`
+	DomainID   int      `json:"domainId"`
+	Activities int      `json:"activities"`
+	Benchmarks []string `json:"benchmarks"`
+}
+
+// BenchmarkCoverage is a benchmark's crosswalk resolved to HAAI activities,
+// with the physical categories measured by it, by other benchmarks or by none.
+type BenchmarkCoverage struct {
+	Benchmark   string                     `json:"benchmark"`
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Categories  []BenchmarkCategory        `json:"categories"`
+	Physical    []PhysicalCategoryCoverage `json:"physicalCategories"`
+}
+
+// Gaps returns the physical categories that no benchmark covers.
+func (b *BenchmarkCoverage) Gaps() []PhysicalCategoryCoverage {
+	var gaps []PhysicalCategoryCoverage
+	for _, p := range b.Physical {
+		if len(p.Benchmarks) == 0 {
+			gaps = append(gaps, p)
+		}
+	}
+	return gaps
+}
+
+// Covers reports whether the benchmark measures activities in p.
+func (b *BenchmarkCoverage) Covers(p PhysicalCategoryCoverage) bool {
+	return containsString(p.Benchmarks, b.Benchmark)
+}
+
+// BenchmarkCoverage resolves the mappings.json crosswalk of a benchmark
+// (behavior1k or activitynet).
+func (ds *Dataset) BenchmarkCoverage(benchmark string) (*BenchmarkCoverage, error) {
+	benchmark = strings.ToLower(benchmark)
+	refs, err := ds.benchmarkRefs()
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(Benchmarks(), benchmark) {
+		return nil, fmt.Errorf("unknown benchmark %q (use %s)", benchmark, strings.Join(Benchmarks(), " or "))
+	}
+
+	b := &BenchmarkCoverage{Benchmark: benchmark}
+	if src, ok := ds.mappings.Sources[benchmark]; ok {
+		b.Name = src.Name
+	}
+	add := func(name string, examples []string, r []HAAIRef) {
+		acts, _ := ds.ResolveRefs(r)
+		c := BenchmarkCategory{Name: name, Examples: examples, Refs: r, Activities: acts, Capability: make(map[string]int)}
+		bottlenecks := make(map[string]int)
+		for _, a := range acts {
+			c.Capability[a.Scores.AICapability]++
+			if a.Scores.Bottleneck != "" {
+				bottlenecks[a.Scores.Bottleneck]++
+			}
+		}
+		for _, bn := range sortedKeys(bottlenecks) {
+			if bottlenecks[bn] > bottlenecks[c.Bottleneck] {
+				c.Bottleneck = bn
+			}
+		}
+		b.Categories = append(b.Categories, c)
+	}
+	switch benchmark {
+	case BenchmarkBehavior1K:
+		b.Description = ds.mappings.Behavior1KMapping.Description
+		for _, e := range ds.mappings.Behavior1KMapping.Mappings {
+			add(e.BehaviorCategory, e.TaskExamples, e.HAAICategories)
+		}
+	case BenchmarkActivityNet:
+		b.Description = ds.mappings.ActivityNetMapping.Description
+		for _, e := range ds.mappings.ActivityNetMapping.Mappings {
+			add(e.ActionType, e.Examples, e.HAAIDomains)
+		}
+	}
+
+	// A category is covered if a benchmark names it or its whole domain
+	covered := make(map[string][]string)
+	for _, name := range Benchmarks() {
+		seen := make(map[string]bool)
+		for _, r := range refs[name] {
+			acts, _ := ds.ResolveRef(r)
+			for _, a := range acts {
+				if !seen[a.CategoryID] {
+					seen[a.CategoryID] = true
+					covered[a.CategoryID] = append(covered[a.CategoryID], name)
+				}
+			}
+		}
+	}
+	counts := make(map[string]int)
+	for _, a := range ds.activities {
+		counts[a.CategoryID]++
+	}
+	for _, d := range ds.Domains() {
+		if d.ID < firstPhysicalDomain {
+			continue
+		}
+		for _, c := range d.Categories {
+			b.Physical = append(b.Physical, PhysicalCategoryCoverage{
+				Category:   c,
+				DomainID:   d.ID,
+				Activities: counts[c.ID],
+				Benchmarks: covered[c.ID],
+			})
+		}
+	}
+	return b, nil
+}
+
+// benchmarkRefs returns every benchmark's HAAI references, checking that
+// they exist
+func (ds *Dataset) benchmarkRefs() (map[string][]HAAIRef, error) {
+	refs := make(map[string][]HAAIRef)
+	for _, e := range ds.mappings.Behavior1KMapping.Mappings {
+		refs[BenchmarkBehavior1K] = append(refs[BenchmarkBehavior1K], e.HAAICategories...)
+	}
+	for _, e := range ds.mappings.ActivityNetMapping.Mappings {
+		refs[BenchmarkActivityNet] = append(refs[BenchmarkActivityNet], e.HAAIDomains...)
+	}
+	for name, rs := range refs {
+		for _, r := range rs {
+			if _, err := ds.ResolveRef(r); err != nil {
+				return nil, fmt.Errorf("mappings.json: %s mapping: %w", name, err)
+			}
+		}
+	}
+	return refs, nil
+}
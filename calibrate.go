@@ -0,0 +1,622 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DeploymentEvents returns the events in deployments.json, oldest first,
+// after checking their dates, targets and capability levels.
+func (ds *Dataset) DeploymentEvents() ([]DeploymentEvent, error) {
+	var df DeploymentsFile
+	if err := ds.loadJSON("deployments.json", &df); err != nil {
+		return nil, err
+	}
+	for i, e := range df.Events {
+		if _, ok := eventTime(e.Date); !ok {
+			return nil, fmt.Errorf("deployments.json: event %d (%s): invalid date %q", i+1, e.System, e.Date)
+		}
+		if !containsString(ds.CapabilityLevels(), e.Capability) {
+			return nil, fmt.Errorf("deployments.json: event %d (%s): invalid capability %q", i+1, e.System, e.Capability)
+		}
+		if len(e.Targets) == 0 {
+			return nil, fmt.Errorf("deployments.json: event %d (%s): no targets", i+1, e.System)
+		}
+		for _, t := range e.Targets {
+			if _, ok := ds.Category(t); ok {
+				continue
+			}
+			if _, ok := ds.Activity(t); !ok {
+				return nil, fmt.Errorf("deployments.json: event %d (%s): unknown category or activity %q", i+1, e.System, t)
+			}
+		}
+		if e.Alignment == "" {
+			continue
+		}
+		s, ok := ds.AlignedSystem(e.Alignment)
+		if !ok {
+			return nil, fmt.Errorf("deployments.json: event %d (%s): alignment %q not found in mappings.aiCapabilityAlignment", i+1, e.System, e.Alignment)
+		}
+		for _, t := range e.Targets {
+			if !refsCover(s.Refs(), t) {
+				return nil, fmt.Errorf("deployments.json: event %d (%s): target %s is outside the domains and categories of %q", i+1, e.System, t, s.System)
+			}
+		}
+	}
+	sort.SliceStable(df.Events, func(i, j int) bool {
+		ti, _ := eventTime(df.Events[i].Date)
+		tj, _ := eventTime(df.Events[j].Date)
+		return ti.Before(tj)
+	})
+	return df.Events, nil
+}
+
+// Covers reports whether the event applies to the activity, directly or
+// through its category.
+func (e DeploymentEvent) Covers(a *Activity) bool {
+	return containsString(e.Targets, a.ID) || containsString(e.Targets, a.CategoryID)
+}
+
+// AlignedSystem returns the mappings.aiCapabilityAlignment entry for a system.
+func (ds *Dataset) AlignedSystem(name string) (*AlignedSystem, bool) {
+	systems := ds.mappings.AICapabilityAlignment.Systems
+	for i := range systems {
+		if systems[i].System == name {
+			return &systems[i], true
+		}
+	}
+	return nil, false
+}
+
+// refsCover reports whether a category or activity ID falls under any of
+// the references
+func refsCover(refs []HAAIRef, id string) bool {
+	for _, r := range refs {
+		if first, last, ok := r.Domains(); ok {
+			if d := DomainFromID(id); d >= first && d <= last {
+				return true
+			}
+			continue
+		}
+		if id == string(r) || strings.HasPrefix(id, string(r)+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// WaveList is a predicted wave written as a single number or a list.
+type WaveList []int
+
+func (w *WaveList) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*w = WaveList{n}
+		return nil
+	}
+	var waves []int
+	if err := json.Unmarshal(data, &waves); err != nil {
+		return fmt.Errorf("predicted wave must be a number or a list of numbers, got %s", data)
+	}
+	*w = waves
+	return nil
+}
+
+// Span returns the window from the start of the earliest wave to the end of
+// the latest, or false if a wave has no window.
+func (w WaveList) Span(windows map[int]WaveWindow) (WaveWindow, bool) {
+	var span WaveWindow
+	if len(w) == 0 {
+		return span, false
+	}
+	for i, wave := range w {
+		win, ok := windows[wave]
+		if !ok {
+			return WaveWindow{}, false
+		}
+		if i == 0 || win.Start < span.Start {
+			span.Start = win.Start
+		}
+		if i == 0 || span.End != 0 && (win.End == 0 || win.End > span.End) {
+			span.End = win.End
+		}
+	}
+	return span, true
+}
+
+// eventTime parses YYYY-MM-DD, YYYY-MM or YYYY as the first day of the period
+func eventTime(date string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(date)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// CalibrationOptions configures Calibrate.
+type CalibrationOptions struct {
+	// Level is the capability an activity must reach to count as arrived
+	// (default near_solved). Events at this or a more capable level count.
+	Level string
+
+	// HorizonYears is how far ahead a snapshot's aiCapability is taken to
+	// forecast reaching Level, for Brier scores (default 2).
+	HorizonYears int
+
+	// Today is the date up to which outcomes are known (default: the AsOf
+	// date, or the current date).
+	Today string
+}
+
+// ActivityCalibration compares one activity's predicted wave with events.
+// The prediction is the latest snapshot dated before the activity was
+// reached, so no snapshot is scored against an outcome it already knew;
+// activities reached before any snapshot are "already reached" and left out
+// of the hit rate, error and bias. Error is in years: 0 inside the window,
+// negative when AI arrived before it, positive after it; for overdue
+// activities it is the years since the window closed.
+type ActivityCalibration struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	DomainID      int        `json:"domainId"`
+	Abstraction   int        `json:"abstraction"`
+	PredictedOn   string     `json:"predictedOn"`
+	PredictedWave int        `json:"predictedWave"`
+	Window        WaveWindow `json:"window"`
+	ReachedOn     string     `json:"reachedOn,omitempty"`
+	ReachedBy     string     `json:"reachedBy,omitempty"`
+	Verdict       string     `json:"verdict"` // hit, early, late, overdue, pending or already reached
+	Error         int        `json:"error"`
+}
+
+// SystemCalibration compares the waves mappings.aiCapabilityAlignment
+// predicted for an AI system with the events recorded for it. The window
+// spans the predicted waves; Verdict and Error read as for activities, and
+// Verdict is "no events" when deployments.json has none for the system.
+type SystemCalibration struct {
+	System        string     `json:"system"`
+	Status        string     `json:"alignmentStatus"`
+	PredictedWave []int      `json:"predictedWave"`
+	Window        WaveWindow `json:"window"`
+	Events        int        `json:"events"`
+	ReachedOn     string     `json:"reachedOn,omitempty"`
+	Verdict       string     `json:"verdict"`
+	Error         int        `json:"error"`
+}
+
+// CalibrationStats summarises a group of activities. Statistics without
+// data are nil.
+type CalibrationStats struct {
+	Covered   int      `json:"covered"`        // activities with at least one event
+	Already   int      `json:"alreadyReached"` // reached before any snapshot predicted them
+	Reached   int      `json:"reached"`
+	Hits      int      `json:"hits"`
+	Overdue   int      `json:"overdue"`
+	HitRate   *float64 `json:"hitRate"` // hits / (reached + overdue)
+	MAE       *float64 `json:"meanAbsoluteError"`
+	Bias      *float64 `json:"bias"` // mean signed error of reached activities
+	Forecasts int      `json:"forecasts"`
+	Brier     *float64 `json:"brier"`
+
+	absErr, signedErr, brier float64
+}
+
+// DomainCalibration is the calibration of one domain's activities.
+type DomainCalibration struct {
+	DomainID int    `json:"domainId"`
+	Name     string `json:"name"`
+	CalibrationStats
+}
+
+// AbstractionCalibration is the mean arrival of reached activities at one
+// abstraction level, as a decimal year.
+type AbstractionCalibration struct {
+	Abstraction int     `json:"abstraction"`
+	Reached     int     `json:"reached"`
+	MeanYear    float64 `json:"meanYear"`
+}
+
+// Calibration scores the taxonomy's predictions against deployment events.
+// AbstractFirst is Spearman's rank correlation between abstraction and the
+// date an activity was reached; it is positive when more abstract activities
+// (lower abstraction scores) arrived first.
+type Calibration struct {
+	Level        string `json:"level"`
+	HorizonYears int    `json:"horizonYears"`
+	Today        string `json:"today"`
+	Events       int    `json:"events"`
+	CalibrationStats
+	Domains       []DomainCalibration      `json:"domains"`
+	ByAbstraction []AbstractionCalibration `json:"byAbstraction"`
+	AbstractFirst *float64                 `json:"abstractFirst"`
+	Activities    []ActivityCalibration    `json:"activities"`
+	Systems       []SystemCalibration      `json:"systems"`
+}
+
+// Calibrate compares each activity's predicted agiWave window, taken from
+// the first snapshot that assessed it, with the date deployment events show
+// it reaching opts.Level. It reports the hit rate, the mean absolute timing
+// error and the bias, overall and per domain, and Brier scores for every
+// snapshot's aiCapability read as the probability of reaching Level within
+// the horizon (linear in capability rank: not_attempted 0 to solved 1).
+// Only activities covered by at least one event are scored, since the events
+// file is not an exhaustive record. Each system in
+// mappings.aiCapabilityAlignment is scored the same way against the events
+// aligned to it, using the wave windows of the latest snapshot taken before
+// the system was reached.
+func (ds *Dataset) Calibrate(opts CalibrationOptions) (*Calibration, error) {
+	if opts.Level == "" {
+		opts.Level = "near_solved"
+	}
+	if !containsString(ds.CapabilityLevels(), opts.Level) {
+		return nil, fmt.Errorf("invalid level %q (allowed: %s)", opts.Level, strings.Join(ds.CapabilityLevels(), ", "))
+	}
+	if opts.HorizonYears <= 0 {
+		opts.HorizonYears = 2
+	}
+	if opts.Today == "" {
+		opts.Today = ds.asOf
+	}
+	if opts.Today == "" {
+		opts.Today = time.Now().Format("2006-01-02")
+	}
+	today, err := time.Parse("2006-01-02", opts.Today)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", opts.Today)
+	}
+
+	events, err := ds.DeploymentEvents()
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []calibrationSnapshot
+	dates, err := ds.AssessmentDates()
+	if err != nil {
+		return nil, err
+	}
+	for _, date := range dates {
+		if date > opts.Today || (ds.asOf != "" && date > ds.asOf) {
+			break
+		}
+		af, err := ds.LoadAssessment(date)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, calibrationSnapshot{af.AssessmentDate, af.Entries(), af.WaveWindows()})
+	}
+
+	c := &Calibration{Level: opts.Level, HorizonYears: opts.HorizonYears, Today: opts.Today, Events: len(events)}
+	domains := make(map[int]*CalibrationStats)
+	levels := make(map[int]*AbstractionCalibration)
+	var abstraction, arrival []float64
+	threshold := ds.capabilityRank(opts.Level)
+
+	// Arrival order tests abstract first whether or not a snapshot
+	// predicted the activity in time
+	arrived := func(a *Activity, at time.Time) {
+		lvl := levels[a.Scores.Abstraction]
+		if lvl == nil {
+			lvl = &AbstractionCalibration{Abstraction: a.Scores.Abstraction}
+			levels[a.Scores.Abstraction] = lvl
+		}
+		lvl.Reached++
+		lvl.MeanYear += decimalYear(at)
+		abstraction = append(abstraction, float64(a.Scores.Abstraction))
+		arrival = append(arrival, decimalYear(at))
+	}
+
+	for i := range ds.activities {
+		a := &ds.activities[i]
+		var covering []DeploymentEvent
+		for _, e := range events {
+			if e.Covers(a) {
+				covering = append(covering, e)
+			}
+		}
+		if len(covering) == 0 {
+			continue
+		}
+		var reached *DeploymentEvent
+		var reachedAt time.Time
+		for j := range covering {
+			if ds.capabilityRank(covering[j].Capability) >= threshold {
+				reached = &covering[j]
+				reachedAt, _ = eventTime(reached.Date)
+				break
+			}
+		}
+		if reached != nil && reachedAt.After(today) {
+			reached = nil
+		}
+
+		stats := domains[a.DomainID]
+		if stats == nil {
+			stats = &CalibrationStats{}
+			domains[a.DomainID] = stats
+		}
+		groups := []*CalibrationStats{&c.CalibrationStats, stats}
+
+		before := reachedAt
+		if reached == nil {
+			before = today.AddDate(0, 0, 1)
+		}
+		ac, ok := ds.predictedWindow(a, snapshots, before)
+		if !ok && reached != nil {
+			// reached before any snapshot assessed it: nothing to score
+			if ac, ok = ds.predictedWindow(a, snapshots, time.Time{}); !ok {
+				continue
+			}
+			ac.ReachedOn = reached.Date
+			ac.ReachedBy = reached.System
+			ac.Verdict = "already reached"
+			arrived(a, reachedAt)
+			for _, g := range groups {
+				g.Covered++
+				g.Already++
+			}
+			c.Activities = append(c.Activities, ac)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		for _, g := range groups {
+			g.Covered++
+		}
+
+		// Brier scores for every snapshot whose horizon has resolved and
+		// that was taken before the activity was reached
+		for _, snap := range snapshots {
+			aa, ok := snap.entries[a.ID]
+			if !ok {
+				continue
+			}
+			at, err := time.Parse("2006-01-02", snap.date)
+			if err != nil || (reached != nil && !at.Before(reachedAt)) {
+				continue
+			}
+			end := at.AddDate(opts.HorizonYears, 0, 0)
+			outcome := 0.0
+			switch {
+			case reached != nil && !reachedAt.After(end):
+				outcome = 1
+			case end.After(today):
+				continue
+			}
+			p := ds.capabilityProbability(aa.AICapability)
+			for _, g := range groups {
+				g.Forecasts++
+				g.brier += (p - outcome) * (p - outcome)
+			}
+		}
+
+		switch {
+		case reached != nil:
+			ac.ReachedOn = reached.Date
+			ac.ReachedBy = reached.System
+			ac.Verdict, ac.Error = timingVerdict(ac.Window, reachedAt.Year())
+			arrived(a, reachedAt)
+			for _, g := range groups {
+				g.Reached++
+				g.absErr += math.Abs(float64(ac.Error))
+				g.signedErr += float64(ac.Error)
+				if ac.Verdict == "hit" {
+					g.Hits++
+				}
+			}
+		case ac.Window.End != 0 && today.Year() > ac.Window.End:
+			ac.Verdict = "overdue"
+			ac.Error = today.Year() - ac.Window.End
+			for _, g := range groups {
+				g.Overdue++
+			}
+		default:
+			ac.Verdict = "pending"
+		}
+		c.Activities = append(c.Activities, ac)
+	}
+
+	c.CalibrationStats.finish()
+	for _, d := range ds.taxonomy.Domains {
+		if stats, ok := domains[d.ID]; ok {
+			stats.finish()
+			c.Domains = append(c.Domains, DomainCalibration{DomainID: d.ID, Name: d.Name, CalibrationStats: *stats})
+		}
+	}
+	for _, k := range sortedIntKeys(levels) {
+		lvl := levels[k]
+		lvl.MeanYear /= float64(lvl.Reached)
+		c.ByAbstraction = append(c.ByAbstraction, *lvl)
+	}
+	if len(arrival) >= 3 {
+		c.AbstractFirst = finite(spearman(abstraction, arrival))
+	}
+
+	for _, sys := range ds.mappings.AICapabilityAlignment.Systems {
+		sc := SystemCalibration{System: sys.System, Status: sys.AlignmentStatus, PredictedWave: sys.PredictedWave}
+		var reachedAt time.Time
+		for _, e := range events {
+			if e.Alignment != sys.System {
+				continue
+			}
+			sc.Events++
+			at, _ := eventTime(e.Date)
+			if sc.ReachedOn == "" && ds.capabilityRank(e.Capability) >= threshold && !at.After(today) {
+				sc.ReachedOn, reachedAt = e.Date, at
+			}
+		}
+
+		// Windows from the latest snapshot taken before the system was
+		// reached, as for activities
+		var snap *calibrationSnapshot
+		for i := len(snapshots) - 1; i >= 0; i-- {
+			at, err := time.Parse("2006-01-02", snapshots[i].date)
+			if err == nil && (sc.ReachedOn == "" || at.Before(reachedAt)) {
+				snap = &snapshots[i]
+				break
+			}
+		}
+		if snap == nil && len(snapshots) > 0 {
+			snap = &snapshots[0]
+			sc.Verdict = "already reached"
+		}
+		if snap == nil {
+			continue
+		}
+		win, ok := sys.PredictedWave.Span(snap.windows)
+		if !ok {
+			return nil, fmt.Errorf("mappings.json: aiCapabilityAlignment %q: predicted wave %v has no agiWaveTimelines window in %s", sys.System, []int(sys.PredictedWave), snap.date)
+		}
+		sc.Window = win
+		switch {
+		case sc.Verdict != "":
+		case sc.Events == 0:
+			sc.Verdict = "no events"
+		case sc.ReachedOn != "":
+			sc.Verdict, sc.Error = timingVerdict(win, reachedAt.Year())
+		case win.End != 0 && today.Year() > win.End:
+			sc.Verdict = "overdue"
+			sc.Error = today.Year() - win.End
+		default:
+			sc.Verdict = "pending"
+		}
+		c.Systems = append(c.Systems, sc)
+	}
+	return c, nil
+}
+
+// timingVerdict places the year something was reached against a window:
+// hit inside it, early or late outside it with the error in years
+func timingVerdict(w WaveWindow, year int) (string, int) {
+	switch {
+	case w.Contains(year):
+		return "hit", 0
+	case year < w.Start:
+		return "early", year - w.Start
+	default:
+		return "late", year - w.End
+	}
+}
+
+// calibrationSnapshot is an assessment decoded once for Calibrate
+type calibrationSnapshot struct {
+	date    string
+	entries map[string]ActivityAssessment
+	windows map[int]WaveWindow
+}
+
+// predictedWindow returns the wave and window predicted for a in the latest
+// snapshot dated before the given time, or in the earliest snapshot that
+// assessed it if before is zero
+func (ds *Dataset) predictedWindow(a *Activity, snapshots []calibrationSnapshot, before time.Time) (ActivityCalibration, bool) {
+	for i := range snapshots {
+		snap := snapshots[i]
+		if !before.IsZero() {
+			snap = snapshots[len(snapshots)-1-i]
+			if at, err := time.Parse("2006-01-02", snap.date); err != nil || !at.Before(before) {
+				continue
+			}
+		}
+		aa, ok := snap.entries[a.ID]
+		if !ok {
+			continue
+		}
+		win, ok := snap.windows[aa.AGIWave]
+		if !ok {
+			return ActivityCalibration{}, false
+		}
+		return ActivityCalibration{
+			ID:            a.ID,
+			Name:          a.Name,
+			DomainID:      a.DomainID,
+			Abstraction:   a.Scores.Abstraction,
+			PredictedOn:   snap.date,
+			PredictedWave: aa.AGIWave,
+			Window:        win,
+		}, true
+	}
+	return ActivityCalibration{}, false
+}
+
+// capabilityProbability reads a capability level as a probability, linear
+// in rank from the least capable level (0) to the most capable (1)
+func (ds *Dataset) capabilityProbability(level string) float64 {
+	n := len(ds.CapabilityLevels())
+	if n < 2 {
+		return 0
+	}
+	return float64(ds.capabilityRank(level)-1) / float64(n-1)
+}
+
+func (s *CalibrationStats) finish() {
+	if scored := s.Reached + s.Overdue; scored > 0 {
+		s.HitRate = finite(float64(s.Hits) / float64(scored))
+	}
+	if s.Reached > 0 {
+		s.MAE = finite(s.absErr / float64(s.Reached))
+		s.Bias = finite(s.signedErr / float64(s.Reached))
+	}
+	if s.Forecasts > 0 {
+		s.Brier = finite(s.brier / float64(s.Forecasts))
+	}
+}
+
+// decimalYear returns t as a fractional year (2024-07-02 is about 2024.5)
+func decimalYear(t time.Time) float64 {
+	start := time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+	return float64(t.Year()) + t.Sub(start).Hours()/end.Sub(start).Hours()
+}
+
+// spearman is the rank correlation of x and y, with tied values given their average rank
+func spearman(x, y []float64) float64 {
+	rx, ry := ranks(x), ranks(y)
+	// ranks always average (n+1)/2
+	mx := float64(len(x)+1) / 2
+	my := mx
+	var cov, vx, vy float64
+	for i := range rx {
+		cov += (rx[i] - mx) * (ry[i] - my)
+		vx += (rx[i] - mx) * (rx[i] - mx)
+		vy += (ry[i] - my) * (ry[i] - my)
+	}
+	if vx == 0 || vy == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(vx*vy)
+}
+
+func ranks(v []float64) []float64 {
+	order := make([]int, len(v))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return v[order[a]] < v[order[b]] })
+	r := make([]float64, len(v))
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && v[order[j+1]] == v[order[i]] {
+			j++
+		}
+		avg := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			r[order[k]] = avg
+		}
+		i = j + 1
+	}
+	return r
+}
+
+func sortedIntKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
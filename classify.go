@@ -0,0 +1,189 @@
+package haai
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Classification ranks the categories a free-text activity description most
+// likely belongs to, with the taxonomy rules that help decide between them.
+type Classification struct {
+	Text       string              `json:"text"`
+	Candidates []CategoryCandidate `json:"candidates"`
+
+	// BoundaryRules are the rules between the domains of the top candidates
+	BoundaryRules []BoundaryRule `json:"boundaryRules"`
+
+	// Examples are multi-domain examples that resemble the text
+	Examples []MultiDomainExample `json:"examples"`
+
+	// ContextModifiers are actions in the text whose category depends on
+	// whether it is done professionally or personally
+	ContextModifiers []ContextModifier `json:"contextModifiers"`
+}
+
+// CategoryCandidate is one ranked category. Share is the candidate's score
+// as a fraction of the total over all candidates.
+type CategoryCandidate struct {
+	Category   Category      `json:"category"`
+	DomainID   int           `json:"domainId"`
+	Score      float64       `json:"score"`
+	Share      float64       `json:"share"`
+	Activities []string      `json:"activities"` // best matching activity IDs in the category
+	Matches    []SearchMatch `json:"matches"`    // matched text of the best activity
+}
+
+// classifyWeights are the per-field weights (parallel to SearchFields) used
+// for classification. Words from a domain's exclusion criteria count against
+// every activity in it.
+var classifyWeights = []float64{2, 2, 1.5, 1.5, 0.5, 1, -1}
+
+// classifyBoundaryCandidates is how many top candidates are compared for boundary rules
+const classifyBoundaryCandidates = 3
+
+// Classify ranks categories for a free-text activity description by lexical
+// similarity to the activities already in the taxonomy (names, descriptions,
+// example tasks) and to the category and domain texts. A category scores the
+// sum of its three best activities, weighted 1, 1/2 and 1/4. The result is
+// deterministic and needs no network access. limit <= 0 returns every
+// category with a positive score.
+func (ds *Dataset) Classify(text string, limit int) *Classification {
+	c := &Classification{Text: text}
+
+	byCategory := make(map[string][]SearchHit)
+	var order []string
+	for _, h := range ds.rank(text, classifyWeights, 0) {
+		id := h.Activity.CategoryID
+		if _, ok := byCategory[id]; !ok {
+			order = append(order, id)
+		}
+		byCategory[id] = append(byCategory[id], h)
+	}
+
+	total := 0.0
+	for _, id := range order {
+		hits := byCategory[id]
+		cat, ok := ds.Category(id)
+		if !ok {
+			continue
+		}
+		cand := CategoryCandidate{Category: *cat, DomainID: DomainFromID(id), Matches: positiveMatches(hits[0].Matches)}
+		weight := 1.0
+		for i, h := range hits {
+			if i == 3 {
+				break
+			}
+			cand.Score += weight * h.Score
+			cand.Activities = append(cand.Activities, h.Activity.ID)
+			weight /= 2
+		}
+		total += cand.Score
+		c.Candidates = append(c.Candidates, cand)
+	}
+	sort.SliceStable(c.Candidates, func(i, j int) bool {
+		if c.Candidates[i].Score != c.Candidates[j].Score {
+			return c.Candidates[i].Score > c.Candidates[j].Score
+		}
+		return CompareIDs(c.Candidates[i].Category.ID, c.Candidates[j].Category.ID) < 0
+	})
+	for i := range c.Candidates {
+		c.Candidates[i].Share = c.Candidates[i].Score / total
+	}
+	if limit > 0 && len(c.Candidates) > limit {
+		c.Candidates = c.Candidates[:limit]
+	}
+
+	var top []int
+	for i, cand := range c.Candidates {
+		if i == classifyBoundaryCandidates {
+			break
+		}
+		top = append(top, cand.DomainID)
+	}
+	c.BoundaryRules = ds.BoundaryRulesBetween(top...)
+
+	rules := ds.taxonomy.ClassificationRules
+	words := stemSet(text)
+	for _, ex := range rules.MultiDomainActivities.Examples {
+		if overlap(stemSet(ex.Activity), words) >= 0.5 {
+			c.Examples = append(c.Examples, ex)
+		}
+	}
+	for _, m := range rules.ContextModifiers.Examples {
+		if overlap(stemSet(m.Action), words) > 0 {
+			c.ContextModifiers = append(c.ContextModifiers, m)
+		}
+	}
+	return c
+}
+
+// BoundaryRulesBetween returns the taxonomy boundary rules whose two domains
+// are both among the given domain IDs.
+func (ds *Dataset) BoundaryRulesBetween(domains ...int) []BoundaryRule {
+	in := make(map[int]bool)
+	for _, d := range domains {
+		in[d] = true
+	}
+	var out []BoundaryRule
+	for _, r := range ds.taxonomy.ClassificationRules.BoundaryRules {
+		if a, b, ok := r.Domains(); ok && a != b && in[a] && in[b] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+var boundaryPattern = regexp.MustCompile(`(\d+)\s*vs\.?\s*(?:Domain\s*)?(\d+)`)
+
+// Domains parses the two domain IDs from a boundary such as "Domain 4 vs 5".
+func (r BoundaryRule) Domains() (int, int, bool) {
+	m := boundaryPattern.FindStringSubmatch(r.Boundary)
+	if m == nil {
+		return 0, 0, false
+	}
+	a, _ := strconv.Atoi(m[1])
+	b, _ := strconv.Atoi(m[2])
+	return a, b, true
+}
+
+// CategoryID returns the category ID at the start of ClassifyAs ("4.4 Informational Exchange" -> "4.4").
+func (e MultiDomainExample) CategoryID() string {
+	id, _, _ := strings.Cut(strings.TrimSpace(e.ClassifyAs), " ")
+	return id
+}
+
+// positiveMatches drops matches that counted against the activity
+func positiveMatches(matches []SearchMatch) []SearchMatch {
+	var out []SearchMatch
+	for _, m := range matches {
+		if m.Score > 0 {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// stemSet returns the distinct stems in text
+func stemSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, t := range tokenize(text) {
+		set[t.term] = true
+	}
+	return set
+}
+
+// overlap is the fraction of a's stems that also occur in b
+func overlap(a, b map[string]bool) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+	n := 0
+	for t := range a {
+		if b[t] {
+			n++
+		}
+	}
+	return float64(n) / float64(len(a))
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdAssessment dispatches the assessment subcommands that write snapshots
+func cmdAssessment(ds *haai.Dataset, args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: haai assessment <new|set> [arguments]")
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "new":
+		cmdAssessmentNew(ds, args[1:])
+	case "set":
+		cmdAssessmentSet(ds, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown assessment command: %s (use new or set)\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// cmdAssessmentNew scaffolds a new snapshot from the latest one
+func cmdAssessmentNew(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("assessment new", flag.ExitOnError)
+	date := fs.String("date", time.Now().Format("2006-01-02"), "date of the new snapshot (YYYY-MM-DD)")
+	message := fs.String("message", "", "changeLog text for the new snapshot")
+	fs.Parse(args)
+
+	doc, err := ds.NewAssessment(*date, *message)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if doc.Exists() {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists\n", doc.Path())
+		os.Exit(1)
+	}
+	if err := doc.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Created %s from %s\n", doc.Path(), doc.Source)
+	if missing := doc.Missing(); len(missing) > 0 {
+		fmt.Printf("Warning: %d activities have no assessment entry: %s\n", len(missing), strings.Join(missing, ", "))
+	}
+}
+
+// cmdAssessmentSet updates one activity's entry in a snapshot
+func cmdAssessmentSet(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("assessment set", flag.ExitOnError)
+	date := fs.String("date", "", "snapshot to edit (default: the current assessment)")
+	positional := parseFlags(fs, args)
+	if len(positional) < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: haai assessment set <id> capability=<level> bottleneck=<type> wave=<n> [--date YYYY-MM-DD]")
+		os.Exit(1)
+	}
+	id := positional[0]
+
+	target := *date
+	if target == "" {
+		target = strings.TrimSuffix(filepath.Base(ds.AssessmentFile()), ".json")
+	}
+	doc, err := ds.OpenAssessment(target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	aa, existed := doc.Get(id)
+	set := make(map[string]bool)
+	for _, arg := range positional[1:] {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Invalid assignment: %s (want key=value)\n", arg)
+			os.Exit(1)
+		}
+		switch key {
+		case "capability", "aiCapability":
+			aa.AICapability = value
+			set["capability"] = true
+		case "bottleneck":
+			aa.Bottleneck = value
+			set["bottleneck"] = true
+		case "wave", "agiWave":
+			wave, err := strconv.Atoi(value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid wave: %s\n", value)
+				os.Exit(1)
+			}
+			aa.AGIWave = wave
+			set["wave"] = true
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown field: %s (use capability, bottleneck or wave)\n", key)
+			os.Exit(1)
+		}
+	}
+	if !existed && len(set) < 3 {
+		fmt.Fprintf(os.Stderr, "Error: %s has no entry in %s; capability, bottleneck and wave are all required\n", id, doc.Path())
+		os.Exit(1)
+	}
+
+	if err := doc.Set(id, aa); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := doc.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Updated %s in %s: capability=%s bottleneck=%s wave=%d\n",
+		id, doc.Path(), aa.AICapability, aa.Bottleneck, aa.AGIWave)
+}
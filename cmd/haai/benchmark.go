@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdBenchmark lists a benchmark's categories with their HAAI activities and
+// the physical categories no benchmark measures
+func cmdBenchmark(ds *haai.Dataset, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: haai benchmark <%s>\n", strings.Join(haai.Benchmarks(), "|"))
+		os.Exit(1)
+	}
+	b, err := ds.BenchmarkCoverage(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if machineOutput() {
+		t := newTabular("benchmarkCategory", "activityId", "name", "aiCapability", "bottleneck", "agiWave")
+		for _, c := range b.Categories {
+			for _, a := range c.Activities {
+				t.add(c.Name, a.ID, a.Name, a.Scores.AICapability, a.Scores.Bottleneck, a.Scores.AGIWave)
+			}
+		}
+		emit(b, t)
+		return
+	}
+
+	name := b.Name
+	if name == "" {
+		name = b.Benchmark
+	}
+	fmt.Printf("%s: %s\n", name, b.Description)
+	for _, c := range b.Categories {
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Printf("%s -> %s (%d activities, main bottleneck: %s)\n", c.Name, joinRefs(c.Refs), len(c.Activities), c.Bottleneck)
+		fmt.Printf("  Examples: %s\n", strings.Join(c.Examples, ", "))
+		var caps []string
+		for _, level := range ds.CapabilityLevels() {
+			if n := c.Capability[level]; n > 0 {
+				caps = append(caps, fmt.Sprintf("%s %d", level, n))
+			}
+		}
+		fmt.Printf("  Capability: %s\n", strings.Join(caps, ", "))
+		for _, a := range c.Activities {
+			name := a.Name
+			if len(name) > 40 {
+				name = name[:37] + "..."
+			}
+			fmt.Printf("  %-8s %-40s %-14s %s\n", a.ID, name, a.Scores.AICapability, a.Scores.Bottleneck)
+		}
+	}
+
+	fmt.Println("\nPhysical Category Coverage (domains 7-10):")
+	for _, p := range b.Physical {
+		mark := "GAP"
+		switch {
+		case b.Covers(p):
+			mark = "yes"
+		case len(p.Benchmarks) > 0:
+			mark = "other: " + strings.Join(p.Benchmarks, ", ")
+		}
+		name := p.Category.Name
+		if len(name) > 34 {
+			name = name[:31] + "..."
+		}
+		fmt.Printf("  %-5s %-34s %4d acts  %s\n", p.Category.ID, name, p.Activities, mark)
+	}
+	if gaps := b.Gaps(); len(gaps) > 0 {
+		fmt.Printf("\n%d of %d physical categories are measured by no benchmark\n", len(gaps), len(b.Physical))
+	}
+}
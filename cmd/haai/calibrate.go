@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdCalibrate scores wave predictions against deployments.json
+func cmdCalibrate(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("calibrate", flag.ExitOnError)
+	level := fs.String("level", "near_solved", "capability level at which an activity counts as reached")
+	horizon := fs.Int("horizon", 2, "years a capability assessment forecasts ahead, for Brier scores")
+	today := fs.String("today", "", "date up to which outcomes are known (default: --as-of or today)")
+	fs.Parse(args)
+
+	c, err := ds.Calibrate(haai.CalibrationOptions{Level: *level, HorizonYears: *horizon, Today: *today})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if machineOutput() {
+		t := newTabular("domainId", "domain", "covered", "alreadyReached", "reached", "hits", "overdue", "hitRate", "meanAbsoluteError", "bias", "forecasts", "brier")
+		for _, d := range c.Domains {
+			t.add(d.DomainID, d.Name, d.Covered, d.Already, d.Reached, d.Hits, d.Overdue, optional(d.HitRate),
+				optional(d.MAE), optional(d.Bias), d.Forecasts, optional(d.Brier))
+		}
+		emit(c, t)
+		return
+	}
+
+	fmt.Printf("Prediction Calibration (%d events, reached = %s or better, as of %s)\n", c.Events, c.Level, c.Today)
+	fmt.Println(strings.Repeat("-", 80))
+	if len(c.Activities) == 0 {
+		fmt.Println("No deployment events cover an assessed activity")
+		printSystemCalibration(c.Systems)
+		return
+	}
+	fmt.Printf("Activities with events: %d (reached %d, overdue %d, pending %d, already reached before any snapshot %d)\n",
+		c.Covered, c.Reached, c.Overdue, c.Covered-c.Reached-c.Overdue-c.Already, c.Already)
+	fmt.Printf("Hit rate:               %s (arrival inside the predicted wave window)\n", formatPercent(c.HitRate))
+	fmt.Printf("Mean absolute error:    %s years\n", formatStat(c.MAE))
+	fmt.Printf("Bias:                   %s years (negative = AI arrived earlier than predicted)\n", formatStat(c.Bias))
+	fmt.Printf("Brier score:            %s over %d capability forecasts (%d-year horizon)\n", formatStat(c.Brier), c.Forecasts, c.HorizonYears)
+
+	fmt.Println("\nBy Domain:")
+	fmt.Printf("  %-3s %-34s %5s %7s %6s %7s %7s %6s\n", "ID", "Domain", "Acts", "Reached", "Hits", "MAE", "Bias", "Brier")
+	for _, d := range c.Domains {
+		name := d.Name
+		if len(name) > 34 {
+			name = name[:31] + "..."
+		}
+		fmt.Printf("  %-3d %-34s %5d %7d %6d %7s %7s %6s\n", d.DomainID, name, d.Covered, d.Reached, d.Hits,
+			formatStat(d.MAE), formatStat(d.Bias), formatStat(d.Brier))
+	}
+
+	if len(c.ByAbstraction) > 0 {
+		fmt.Println("\nAbstract First:")
+		for _, l := range c.ByAbstraction {
+			fmt.Printf("  Abstraction %d: %3d reached, mean arrival %.1f\n", l.Abstraction, l.Reached, l.MeanYear)
+		}
+		switch {
+		case c.AbstractFirst == nil:
+			fmt.Println("  Not enough varied arrivals to test the thesis")
+		case *c.AbstractFirst > 0:
+			fmt.Printf("  Spearman rho %.2f: more abstract activities arrived first (thesis holds)\n", *c.AbstractFirst)
+		default:
+			fmt.Printf("  Spearman rho %.2f: more abstract activities did not arrive first (thesis not supported)\n", *c.AbstractFirst)
+		}
+	}
+
+	fmt.Println("\nActivities:")
+	fmt.Printf("  %-8s %-32s %-5s %-10s %-11s %-15s %s\n", "ID", "Name", "Wave", "Window", "Reached", "Verdict", "Error")
+	for _, a := range c.Activities {
+		name := a.Name
+		if len(name) > 32 {
+			name = name[:29] + "..."
+		}
+		reached := a.ReachedOn
+		if reached == "" {
+			reached = "-"
+		}
+		errYears := "-"
+		if a.Verdict != "pending" && a.Verdict != "already reached" {
+			errYears = fmt.Sprintf("%+d", a.Error)
+		}
+		fmt.Printf("  %-8s %-32s %-5d %-10s %-11s %-15s %s\n", a.ID, name, a.PredictedWave, a.Window, reached, a.Verdict, errYears)
+	}
+	printSystemCalibration(c.Systems)
+}
+
+// printSystemCalibration lists the aiCapabilityAlignment systems against
+// their aligned events
+func printSystemCalibration(systems []haai.SystemCalibration) {
+	if len(systems) == 0 {
+		return
+	}
+	fmt.Println("\nAligned Systems (mappings.aiCapabilityAlignment):")
+	fmt.Printf("  %-38s %-6s %-10s %6s %-11s %-15s %s\n", "System", "Waves", "Window", "Events", "Reached", "Verdict", "Error")
+	for _, s := range systems {
+		name := s.System
+		if len(name) > 38 {
+			name = name[:35] + "..."
+		}
+		reached := s.ReachedOn
+		if reached == "" {
+			reached = "-"
+		}
+		errYears := "-"
+		if (s.ReachedOn != "" && s.Verdict != "already reached") || s.Verdict == "overdue" {
+			errYears = fmt.Sprintf("%+d", s.Error)
+		}
+		fmt.Printf("  %-38s %-6s %-10s %6d %-11s %-15s %s\n", name, joinInts(s.PredictedWave), s.Window, s.Events, reached, s.Verdict, errYears)
+	}
+}
+
+func formatPercent(x *float64) string {
+	if x == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", *x*100)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdClassify suggests HAAI categories for a free-text activity description
+func cmdClassify(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("classify", flag.ExitOnError)
+	limit := fs.Int("limit", 5, "show at most this many categories (0 for all)")
+	text := strings.Join(parseFlags(fs, args), " ")
+	if strings.TrimSpace(text) == "" {
+		fmt.Fprintln(os.Stderr, `Usage: haai classify "<description>" [--limit n]`)
+		os.Exit(1)
+	}
+
+	c := ds.Classify(text, *limit)
+
+	if machineOutput() {
+		t := newTabular("rank", "categoryId", "category", "domainId", "score", "share", "activities")
+		for i, cand := range c.Candidates {
+			t.add(i+1, cand.Category.ID, cand.Category.Name, cand.DomainID,
+				fmt.Sprintf("%.3f", cand.Score), fmt.Sprintf("%.3f", cand.Share), strings.Join(cand.Activities, ","))
+		}
+		emit(c, t)
+		return
+	}
+
+	fmt.Printf("Classification for: %s\n", text)
+	fmt.Println(strings.Repeat("-", 80))
+	if len(c.Candidates) == 0 {
+		fmt.Println("No matching categories; try describing the activity in more words")
+		return
+	}
+	fmt.Printf("%-4s %-6s %-36s %7s %6s  %s\n", "#", "ID", "Category", "Score", "Share", "Closest activities")
+	fmt.Println(strings.Repeat("-", 80))
+	for i, cand := range c.Candidates {
+		name := cand.Category.Name
+		if len(name) > 36 {
+			name = name[:33] + "..."
+		}
+		fmt.Printf("%-4d %-6s %-36s %7.2f %5.0f%%  %s\n", i+1, cand.Category.ID, name,
+			cand.Score, cand.Share*100, strings.Join(cand.Activities, ", "))
+		if len(cand.Matches) > 0 {
+			m := cand.Matches[0]
+			fmt.Printf("                %s: %s\n", m.Field, m.Highlight("[", "]", 60))
+		}
+	}
+
+	if len(c.BoundaryRules) > 0 {
+		fmt.Println("\nBoundary Rules:")
+		for _, r := range c.BoundaryRules {
+			fmt.Printf("  %s: %s\n", r.Boundary, r.Rule)
+		}
+	}
+	if len(c.Examples) > 0 {
+		fmt.Println("\nSimilar Multi-Domain Examples:")
+		for _, ex := range c.Examples {
+			fmt.Printf("  %s -> %s (%s)\n", ex.Activity, ex.ClassifyAs, ex.Rationale)
+		}
+	}
+	if len(c.ContextModifiers) > 0 {
+		fmt.Println("\nContext Matters:")
+		for _, m := range c.ContextModifiers {
+			fmt.Printf("  %s: professional -> %s; personal -> %s\n", m.Action, m.ProfessionalContext, m.PersonalContext)
+		}
+	}
+}
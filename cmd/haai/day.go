@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdDay profiles how much of a day is automatable, from a time diary or
+// the ATUS population average
+func cmdDay(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("day", flag.ExitOnError)
+	files := parseFlags(fs, args)
+	if len(files) > 1 {
+		fmt.Fprintln(os.Stderr, "Usage: haai day [diary.json]")
+		os.Exit(1)
+	}
+
+	var p *haai.DayProfile
+	var err error
+	if len(files) == 1 {
+		var diary *haai.DayInLifeCoverageTest
+		if diary, err = haai.ReadTimeDiary(files[0]); err == nil {
+			p, err = ds.DiaryDay(diary)
+		}
+	} else {
+		p, err = ds.ATUSDay()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	levels := ds.CapabilityLevels()
+	if machineOutput() {
+		cols := []string{"time", "atusCode", "activity", "minutes", "mapping", "haaiRefs", "activities"}
+		cols = append(cols, levels...)
+		t := newTabular(cols...)
+		for _, s := range p.Slots {
+			row := []any{s.Time, s.ATUSCode, s.Activity, s.Minutes, s.Mapping, joinRefs(s.Refs), s.Activities}
+			for _, level := range levels {
+				row = append(row, s.Capability[level])
+			}
+			t.add(row...)
+		}
+		emit(p, t)
+		return
+	}
+
+	subject := p.Subject
+	if subject == "" {
+		subject = "Time diary"
+	}
+	fmt.Printf("Automatable Day: %s (%s of activity)\n", subject, formatClock(p.TotalMinutes))
+	printAssessmentSource(ds)
+	fmt.Println(strings.Repeat("-", 80))
+	if p.Source == haai.DaySourceATUS {
+		fmt.Printf("%-4s %-40s %7s %6s  %s\n", "Code", "ATUS Category", "Min/Day", "Partic", "HAAI")
+	} else {
+		fmt.Printf("%-5s %-40s %7s  %-10s %s\n", "Time", "Activity", "Minutes", "Mapping", "HAAI")
+	}
+	fmt.Println(strings.Repeat("-", 80))
+	for _, s := range p.Slots {
+		name := s.Activity
+		if len(name) > 40 {
+			name = name[:37] + "..."
+		}
+		if p.Source == haai.DaySourceATUS {
+			fmt.Printf("%-4s %-40s %7.0f %5.0f%%  %s\n", s.ATUSCode, name, s.Minutes, s.ParticipationRate*100, joinRefs(s.Refs))
+		} else {
+			fmt.Printf("%-5s %-40s %7.0f  %-10s %s\n", s.Time, name, s.Minutes, s.Mapping, joinRefs(s.Refs))
+		}
+	}
+
+	fmt.Println("\nMinutes by AI Capability:")
+	for _, level := range levels {
+		m := p.Capability[level]
+		share := 0.0
+		if p.TotalMinutes > 0 {
+			share = m / p.TotalMinutes
+		}
+		fmt.Printf("  %-14s %8s %4.0f%% %s\n", level, formatClock(m), share*100, strings.Repeat("#", int(share*40+0.5)))
+	}
+	if p.Unmapped > 0 {
+		fmt.Printf("  %-14s %8s\n", "unmapped", formatClock(p.Unmapped))
+	}
+
+	var waves []int
+	for w := range p.Waves {
+		if w > 0 {
+			waves = append(waves, w)
+		}
+	}
+	sort.Ints(waves)
+	if len(waves) > 0 {
+		windows := map[int]string{}
+		if af := ds.Assessment(); af != nil {
+			for w, win := range af.WaveWindows() {
+				windows[w] = win.String()
+			}
+		}
+		fmt.Println("\nExpected Automatable Minutes by AGI Wave:")
+		cumulative := 0.0
+		for _, w := range waves {
+			cumulative += p.Waves[w]
+			fmt.Printf("  Wave %d %-10s %8s  (cumulative %s, %.0f%% of the day)\n", w, windows[w],
+				formatClock(p.Waves[w]), formatClock(cumulative), cumulative/p.TotalMinutes*100)
+		}
+	}
+	if p.Human > 0 {
+		fmt.Printf("  %-17s %8s  (%.0f%% of the day; not_attempted or human-essential)\n", "Not automatable",
+			formatClock(p.Human), p.Human/p.TotalMinutes*100)
+	}
+}
+
+// formatClock renders minutes as hours and minutes, e.g. "7h05m"
+func formatClock(minutes float64) string {
+	m := int(minutes + 0.5)
+	return fmt.Sprintf("%dh%02dm", m/60, m%60)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdDiff compares two assessment snapshots
+func cmdDiff(ds *haai.Dataset, args []string) {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: haai diff <dateA> <dateB>")
+		os.Exit(1)
+	}
+
+	from, err := ds.LoadAssessment(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	to, err := ds.LoadAssessment(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	diff := ds.DiffAssessments(from, to)
+
+	if machineOutput() {
+		t := newTabular("id", "name", "domainId", "fromCapability", "toCapability", "fromBottleneck", "toBottleneck", "fromWave", "toWave")
+		for _, c := range diff.Changes {
+			t.add(c.ID, c.Name, c.DomainID, c.From.AICapability, c.To.AICapability,
+				c.From.Bottleneck, c.To.Bottleneck, c.From.AGIWave, c.To.AGIWave)
+		}
+		emit(diff, t)
+		return
+	}
+	printDiff(diff)
+}
+
+func printDiff(diff *haai.AssessmentDiff) {
+	fmt.Printf("Assessment Changes: %s -> %s\n", diff.From, diff.To)
+	fmt.Println(strings.Repeat("=", 90))
+
+	fmt.Printf("\nActivities Changed: %d\n", len(diff.Changes))
+	if len(diff.Changes) > 0 {
+		fmt.Println(strings.Repeat("-", 90))
+		fmt.Printf("%-8s %-30s %-25s %-21s %-6s\n", "ID", "Name", "Capability", "Bottleneck", "Wave")
+		fmt.Println(strings.Repeat("-", 90))
+		for _, c := range diff.Changes {
+			name := c.Name
+			if len(name) > 30 {
+				name = name[:27] + "..."
+			}
+			fmt.Printf("%-8s %-30s %-25s %-21s %-6s\n", c.ID, name,
+				changeCell(c.From.AICapability, c.To.AICapability),
+				changeCell(c.From.Bottleneck, c.To.Bottleneck),
+				changeCell(fmt.Sprint(c.From.AGIWave), fmt.Sprint(c.To.AGIWave)))
+		}
+	}
+	if len(diff.Added) > 0 {
+		fmt.Printf("\nAdded: %s\n", strings.Join(diff.Added, ", "))
+	}
+	if len(diff.Removed) > 0 {
+		fmt.Printf("\nRemoved: %s\n", strings.Join(diff.Removed, ", "))
+	}
+
+	fmt.Println("\nBy Domain:")
+	fmt.Printf("  %-4s %-32s %8s %5s %5s %12s\n", "ID", "Domain", "Changed", "Up", "Down", "Mean Wave")
+	for _, d := range diff.Domains {
+		fmt.Printf("  %-4d %-32s %8d %5d %5d %5.2f->%-5.2f\n",
+			d.DomainID, d.Name, d.Changed, d.CapabilityUp, d.CapabilityDown, d.MeanWaveFrom, d.MeanWaveTo)
+	}
+
+	fmt.Println("\nBy AI Capability:")
+	for _, l := range diff.Capabilities {
+		fmt.Printf("  %-15s %4d -> %4d (%+d)\n", l.Level, l.From, l.To, l.To-l.From)
+	}
+
+	printFieldChanges("AGI Wave Timelines", diff.Timelines)
+	printFieldChanges("Composite Weights", diff.Weights)
+}
+
+func printFieldChanges(title string, changes []haai.FieldChange) {
+	fmt.Printf("\n%s:", title)
+	if len(changes) == 0 {
+		fmt.Println(" unchanged")
+		return
+	}
+	fmt.Println()
+	for _, c := range changes {
+		fmt.Printf("  %s: %q -> %q\n", c.Path, c.From, c.To)
+	}
+}
+
+// changeCell renders "old -> new" or just the value if unchanged
+func changeCell(from, to string) string {
+	if from == to {
+		return from
+	}
+	return from + "->" + to
+}
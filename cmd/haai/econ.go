@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdEconExposure computes workers and value exposed per AGI wave from the
+// activity assessments and compares them with the hand-entered projections
+func cmdEconExposure(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("econ exposure", flag.ExitOnError)
+	tolerance := fs.Float64("tolerance", haai.EconTolerance, "relative difference from the projection reported as a disagreement")
+	if rest := parseFlags(fs, args); len(rest) > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: haai econ exposure [--tolerance 0.25]")
+		os.Exit(1)
+	}
+	e, err := ds.EconExposure(*tolerance)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if machineOutput() {
+		t := newTabular("wave", "workers", "percentOfWorkforce", "valueBillions", "domains",
+			"projectedWorkers", "projectedValueBillions", "projectedDomains", "disagreements")
+		for _, w := range e.Waves {
+			var pw, pv any
+			var pd string
+			if p := w.Projection; p != nil {
+				pw, pv, pd = p.WorkersAffected, p.EconomicValueBillions, joinInts(p.PrimaryDomains)
+			}
+			t.add(w.Wave, w.Workers, w.PercentOfWorkforce, w.ValueBillions, joinInts(w.Domains),
+				pw, pv, pd, strings.Join(w.Disagreements, "; "))
+		}
+		emit(e, t)
+		return
+	}
+
+	fmt.Printf("Computed Economic Exposure (%s %d)\n", e.Currency, e.Year)
+	printAssessmentSource(ds)
+	fmt.Println("Domain workers and value weighted by the share of their activities per level and wave")
+	fmt.Println(strings.Repeat("-", 90))
+	fmt.Printf("%-4s %-28s %9s %8s %-12s %6s %6s  %s\n", "ID", "Domain", "Workers", "Value $B", "Hand-entered", "Ready", "Peak", "Waves 1/2/3/4")
+	fmt.Println(strings.Repeat("-", 90))
+	for _, d := range e.Domains {
+		name := d.Name
+		if len(name) > 28 {
+			name = name[:25] + "..."
+		}
+		ready := d.Capability["solved"] + d.Capability["near_solved"]
+		fmt.Printf("%-4d %-28s %9s %8.0f %-12s %5.0f%% %6d  %s\n", d.DomainID, name, formatNumber(d.Workers),
+			d.ValueBillions, d.Exposure, ready*100, d.PeakWave, formatShares(d.Waves))
+	}
+
+	fmt.Println("\nExposed by AI Capability:")
+	for _, l := range e.Capability {
+		fmt.Printf("  %-14s %7.1fM workers %7.0f $B\n", l.Level, l.Workers/1e6, l.ValueBillions)
+	}
+
+	fmt.Println("\nExposed by AGI Wave (computed vs automationImpactProjections):")
+	fmt.Printf("  %-4s %-10s %9s %9s %9s %9s  %-8s %-8s\n", "Wave", "Timeline", "Workers", "Proj.", "Value $B", "Proj.", "Domains", "Proj.")
+	for _, w := range e.Waves {
+		timeline, pw, pv, pd := "", "-", "-", "-"
+		if p := w.Projection; p != nil {
+			timeline = p.Timeline
+			pw = fmt.Sprintf("%.1fM", float64(p.WorkersAffected)/1e6)
+			pv = fmt.Sprintf("%d", p.EconomicValueBillions)
+			pd = joinInts(p.PrimaryDomains)
+		}
+		fmt.Printf("  %-4d %-10s %8.1fM %9s %9.0f %9s  %-8s %-8s\n", w.Wave, timeline, w.Workers/1e6, pw,
+			w.ValueBillions, pv, joinInts(w.Domains), pd)
+	}
+
+	if d := e.Disagreements(); len(d) > 0 {
+		fmt.Printf("\nDisagreements with the projections (tolerance %.0f%%):\n", e.Tolerance*100)
+		for _, s := range d {
+			fmt.Printf("  ! %s\n", s)
+		}
+	} else {
+		fmt.Printf("\nThe projections agree with the computed exposure within %.0f%%\n", e.Tolerance*100)
+	}
+}
+
+// formatShares renders wave shares 1-4 as percentages, e.g. "40/30/20/10"
+func formatShares(shares map[int]float64) string {
+	parts := make([]string, 4)
+	for w := 1; w <= 4; w++ {
+		parts[w-1] = fmt.Sprintf("%.0f", shares[w]*100)
+	}
+	return strings.Join(parts, "/")
+}
+
+func joinInts(list []int) string {
+	parts := make([]string, len(list))
+	for i, n := range list {
+		parts[i] = fmt.Sprintf("%d", n)
+	}
+	return strings.Join(parts, ",")
+}
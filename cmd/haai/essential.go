@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdEssential lists the most human-essential activities together with the
+// humanEssentiality factors from scoring.json that fired for each one
+func cmdEssential(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("essential", flag.ExitOnError)
+	domain := fs.Int("domain", 0, "only list activities in this domain")
+	limit := fs.Int("limit", 25, "show at most this many activities (0 for all)")
+	fs.Parse(args)
+
+	factors := ds.EssentialityFactors()
+	if len(factors) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: scoring.json defines no humanEssentiality factors")
+		os.Exit(1)
+	}
+
+	type row struct {
+		activity     haai.Activity
+		essentiality haai.Essentiality
+	}
+	var rows []row
+	for _, a := range ds.Activities() {
+		if *domain > 0 && haai.DomainFromID(a.ID) != *domain {
+			continue
+		}
+		e := ds.HumanEssentiality(&a)
+		if e.Score <= 0 {
+			continue
+		}
+		rows = append(rows, row{a, e})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].essentiality.Score > rows[j].essentiality.Score
+	})
+	total := len(rows)
+	if *limit > 0 && len(rows) > *limit {
+		rows = rows[:*limit]
+	}
+	if machineOutput() {
+		type record struct {
+			ID      string   `json:"id"`
+			Name    string   `json:"name"`
+			Score   float64  `json:"score"`
+			Max     float64  `json:"max"`
+			Factors []string `json:"factors"`
+		}
+		records := []record{}
+		t := newTabular("id", "name", "score", "max", "factors")
+		for _, r := range rows {
+			ids := factorIDs(r.essentiality.Factors)
+			records = append(records, record{r.activity.ID, r.activity.Name, r.essentiality.Score, r.essentiality.Max, ids})
+			t.add(r.activity.ID, r.activity.Name, r.essentiality.Score, r.essentiality.Max, strings.Join(ids, ","))
+		}
+		emit(records, t)
+		return
+	}
+
+	fmt.Println("Most Human-Essential Activities")
+	printAssessmentSource(ds)
+	fmt.Println(strings.Repeat("=", 90))
+	fmt.Println("Factors:")
+	for _, f := range factors {
+		fmt.Printf("  %-28s w=%-4g %s\n", f.ID, f.Weight, f.Description)
+	}
+	fmt.Println()
+	fmt.Printf("%-8s %-36s %-7s %s\n", "ID", "Name", "Score", "Factors")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, r := range rows {
+		name := r.activity.Name
+		if len(name) > 36 {
+			name = name[:33] + "..."
+		}
+		fmt.Printf("%-8s %-36s %-7s %s\n", r.activity.ID, name,
+			fmt.Sprintf("%g/%g", r.essentiality.Score, r.essentiality.Max),
+			strings.Join(factorIDs(r.essentiality.Factors), ", "))
+	}
+	fmt.Printf("\nShowing %d of %d activities with at least one factor\n", len(rows), total)
+}
+
+func factorIDs(factors []haai.EssentialityFactor) []string {
+	ids := make([]string, len(factors))
+	for i, f := range factors {
+		ids[i] = f.ID
+	}
+	return ids
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdForecast samples automation dates and reports their P10/P50/P90 for
+// domains, occupation groups, economic value and a domain's activities
+func cmdForecast(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("forecast", flag.ExitOnError)
+	samples := fs.Int("samples", 1000, "Monte Carlo runs")
+	seed := fs.Int64("seed", 1, "random seed")
+	share := fs.Float64("share", 0.5, "share of a group's activities that must be automated")
+	spreadsFile := fs.String("spreads", "", "JSON file of earlier/later wave probabilities per capability level")
+	openYears := fs.Int("open-years", haai.DefaultOpenWindowYears, "assumed length of an open-ended wave window")
+	rest := parseFlags(fs, args)
+	if len(rest) > 1 {
+		fmt.Fprintln(os.Stderr, "Usage: haai forecast [domain] [--samples n] [--seed n] [--share 0.5] [--spreads file] [--open-years n]")
+		os.Exit(1)
+	}
+	domain := 0
+	if len(rest) == 1 {
+		var err error
+		if domain, err = strconv.Atoi(rest[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid domain ID: %s\n", rest[0])
+			os.Exit(1)
+		}
+		if _, ok := ds.Domain(domain); !ok {
+			fmt.Fprintf(os.Stderr, "Domain %d not found\n", domain)
+			os.Exit(1)
+		}
+	}
+
+	opts := haai.ForecastOptions{Samples: *samples, Seed: *seed, Share: *share, OpenWindowYears: *openYears}
+	if *spreadsFile != "" {
+		var err error
+		if opts.Spreads, err = haai.ReadForecastSpreads(*spreadsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	f, err := ds.Forecast(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var activities []haai.ActivityForecast
+	for _, a := range f.Activities {
+		if domain == 0 || haai.DomainFromID(a.ID) == domain {
+			activities = append(activities, a)
+		}
+	}
+
+	if machineOutput() {
+		t := newTabular("kind", "id", "name", "source", "p10", "p50", "p90")
+		for _, g := range f.Domains {
+			t.add("domain", g.ID, g.Name, "", g.P10, g.P50, g.P90)
+		}
+		for _, g := range f.Occupations {
+			t.add("occupation", g.ID, g.Name, "", g.P10, g.P50, g.P90)
+		}
+		t.add("value", f.Value.ID, f.Value.Name, "", f.Value.P10, f.Value.P50, f.Value.P90)
+		for _, a := range activities {
+			t.add("activity", a.ID, a.Name, a.Source, a.P10, a.P50, a.P90)
+		}
+		f.Activities = activities
+		emit(f, t)
+		return
+	}
+
+	var windows []string
+	for _, w := range f.Windows {
+		windows = append(windows, fmt.Sprintf("wave %d %s", w.Wave, w))
+	}
+	fmt.Printf("Automation Date Forecast (%d samples, seed %d)\n", f.Samples, f.Seed)
+	printAssessmentSource(ds)
+	fmt.Printf("Wave windows: %s\n", strings.Join(windows, ", "))
+	fmt.Printf("Group dates are when %.0f%% of the group's weighted activities are automated\n", f.Share*100)
+
+	printGroups := func(title string, groups []haai.GroupForecast) {
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Printf("%-4s %-44s %5s %8s %8s %8s\n", "ID", title, "Acts", "P10", "P50", "P90")
+		fmt.Println(strings.Repeat("-", 80))
+		for _, g := range groups {
+			name := g.Name
+			if len(name) > 44 {
+				name = name[:41] + "..."
+			}
+			fmt.Printf("%-4s %-44s %5d %8.1f %8.1f %8.1f\n", g.ID, name, g.Activities, g.P10, g.P50, g.P90)
+		}
+	}
+	printGroups("Domain", f.Domains)
+	fmt.Println()
+	printGroups("ISCO-08 Major Group", f.Occupations)
+	fmt.Println()
+	v := f.Value
+	fmt.Printf("Economic value (%s): P10 %.1f, P50 %.1f, P90 %.1f\n", v.Name, v.P10, v.P50, v.P90)
+
+	if domain == 0 {
+		fmt.Println("\nRun haai forecast <domain> for per-activity dates")
+		return
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%-8s %-36s %4s %-8s %6s %6s %6s\n", "ID", "Activity", "Wave", "Source", "P10", "P50", "P90")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, a := range activities {
+		name := a.Name
+		if len(name) > 36 {
+			name = name[:33] + "..."
+		}
+		fmt.Printf("%-8s %-36s %4d %-8s %6.1f %6.1f %6.1f\n", a.ID, name, a.AGIWave, a.Source, a.P10, a.P50, a.P90)
+	}
+}
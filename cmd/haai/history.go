@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdHistory shows how one activity's assessment evolved across all snapshots
+func cmdHistory(ds *haai.Dataset, id string) {
+	h, err := ds.ActivityHistory(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if machineOutput() {
+		t := newTabular("date", "present", "aiCapability", "bottleneck", "agiWave", "changed")
+		for _, p := range h.Points {
+			t.add(p.Date, p.Present, p.Assessment.AICapability, p.Assessment.Bottleneck, p.Assessment.AGIWave, strings.Join(p.Changed, ","))
+		}
+		emit(h, t)
+		return
+	}
+
+	fmt.Printf("Assessment History for %s: %s\n", h.ID, h.Name)
+	fmt.Println(strings.Repeat("-", 70))
+	fmt.Printf("%-12s %-15s %-12s %-5s %s\n", "Date", "Capability", "Bottleneck", "Wave", "Transition")
+	fmt.Println(strings.Repeat("-", 70))
+
+	var prev *haai.HistoryPoint
+	for i, p := range h.Points {
+		if !p.Present {
+			fmt.Printf("%-12s %-15s\n", p.Date, "(not assessed)")
+			continue
+		}
+		var notes []string
+		for _, field := range p.Changed {
+			switch field {
+			case "aiCapability":
+				notes = append(notes, fmt.Sprintf("capability %s -> %s", prev.Assessment.AICapability, p.Assessment.AICapability))
+			case "bottleneck":
+				notes = append(notes, fmt.Sprintf("bottleneck %s -> %s", prev.Assessment.Bottleneck, p.Assessment.Bottleneck))
+			case "agiWave":
+				notes = append(notes, fmt.Sprintf("wave %d -> %d", prev.Assessment.AGIWave, p.Assessment.AGIWave))
+			}
+		}
+		marker := " "
+		if len(notes) > 0 {
+			marker = "*"
+		}
+		fmt.Printf("%-12s %-15s %-12s %-5d%s %s\n", p.Date, p.Assessment.AICapability, p.Assessment.Bottleneck,
+			p.Assessment.AGIWave, marker, strings.Join(notes, "; "))
+		prev = &h.Points[i]
+	}
+
+	if len(h.States) == 0 {
+		fmt.Println("\nNo assessments found for this activity")
+		return
+	}
+
+	fmt.Println("\nTime in State:")
+	for _, s := range h.States {
+		suffix := ""
+		if s.Ongoing {
+			suffix = " (ongoing)"
+		}
+		fmt.Printf("  %-15s %s to %s  %5d days%s\n", s.Capability, s.From, s.To, s.Days, suffix)
+	}
+
+	fmt.Printf("\nProgress: %+.2f capability levels per year\n", h.LevelsPerYear)
+
+	if p := h.Prediction; p != nil {
+		fmt.Println("\nWave Prediction:")
+		fmt.Printf("  Predicted:  wave %d (%s)\n", p.PredictedWave, p.Window)
+		if p.SolvedOn != "" {
+			fmt.Printf("  Solved on:  %s\n", p.SolvedOn)
+		} else {
+			fmt.Println("  Solved on:  not yet")
+		}
+		fmt.Printf("  Verdict:    %s\n", p.Verdict)
+	}
+}
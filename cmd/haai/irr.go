@@ -0,0 +1,160 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdIRR dispatches the inter-rater reliability subcommands
+func cmdIRR(ds *haai.Dataset, args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: haai irr <sample|score> [arguments]")
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "sample":
+		cmdIRRSample(ds, args[1:])
+	case "score":
+		cmdIRRScore(ds, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown irr command: %s (use sample or score)\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// cmdIRRSample writes a blank, blinded rating sheet
+func cmdIRRSample(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("irr sample", flag.ExitOnError)
+	n := fs.Int("n", 50, "number of activities to sample")
+	seed := fs.Int64("seed", 0, "random seed (default: time-based, recorded in the sheet)")
+	out := fs.String("out", "", "write the sheet to this file instead of stdout")
+	fs.Parse(args)
+
+	s := *seed
+	if s == 0 {
+		s = time.Now().UnixNano()
+	}
+	data, err := ds.SampleRatingSheet(*n, s).Encode()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote rating sheet with %d items to %s (seed %d)\n", min(*n, len(ds.Activities())), *out, s)
+}
+
+// cmdIRRScore reports agreement between filled-in rating sheets
+func cmdIRRScore(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("irr score", flag.ExitOnError)
+	top := fs.Int("top", 10, "number of most-disputed activities to list (0 for all)")
+	files := parseFlags(fs, args)
+	if len(files) < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: haai irr score <raterA.json> <raterB.json> [more sheets...] [--top n]")
+		os.Exit(1)
+	}
+
+	var sheets []*haai.RatingSheet
+	for _, f := range files {
+		sheet, err := haai.ReadRatingSheet(f)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sheets = append(sheets, sheet)
+	}
+	report, err := ds.ScoreRatings(sheets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *top > 0 && len(report.Disagreements) > *top {
+		report.Disagreements = report.Disagreements[:*top]
+	}
+
+	if machineOutput() {
+		t := newTabular("field", "ordinal", "units", "percentAgreement", "cohensKappa", "fleissKappa", "krippendorffAlpha", "meetsThreshold")
+		for _, f := range report.Fields {
+			t.add(f.Field, f.Ordinal, f.Units, optional(f.PercentAgreement), optional(f.CohensKappa),
+				optional(f.FleissKappa), optional(f.Alpha), f.MeetsThreshold)
+		}
+		emit(report, t)
+		return
+	}
+
+	kappa := "Cohen's kappa, quadratic weights for ordinal fields"
+	if len(report.Raters) > 2 {
+		kappa = "Fleiss' kappa"
+	}
+	fmt.Printf("Inter-Rater Reliability: %d raters (%s), %d items\n", len(report.Raters), strings.Join(report.Raters, ", "), report.Items)
+	fmt.Printf("Kappa: %s; threshold %.2f\n", kappa, report.Threshold)
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%-26s %-8s %6s %7s %7s %7s  %s\n", "Field", "Scale", "Units", "Agree", "Kappa", "Alpha", "Meets")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, f := range report.Fields {
+		scale := "nominal"
+		if f.Ordinal {
+			scale = "ordinal"
+		}
+		agree := "-"
+		if f.PercentAgreement != nil {
+			agree = fmt.Sprintf("%.0f%%", *f.PercentAgreement*100)
+		}
+		meets := "no"
+		if f.MeetsThreshold {
+			meets = "yes"
+		}
+		fmt.Printf("%-26s %-8s %6d %7s %7s %7s  %s\n", f.Field, scale, f.Units, agree,
+			formatStat(f.Kappa()), formatStat(f.Alpha), meets)
+	}
+
+	if len(report.Disagreements) == 0 {
+		fmt.Println("\nRaters agree on every item")
+		return
+	}
+	fmt.Printf("\nMost Disagreement:\n")
+	for _, d := range report.Disagreements {
+		name := d.Activity.Name
+		if len(name) > 40 {
+			name = name[:37] + "..."
+		}
+		fmt.Printf("  %-8s %-40s %5.2f\n", d.Activity.ID, name, d.Score)
+		for _, fd := range d.Fields {
+			var vals []string
+			for i, v := range fd.Values {
+				if v == "" {
+					v = "-"
+				}
+				vals = append(vals, report.Raters[i]+"="+v)
+			}
+			fmt.Printf("           %s: %s\n", fd.Field, strings.Join(vals, "  "))
+		}
+	}
+}
+
+// optional returns *x, or "" if x is nil
+func optional(x *float64) any {
+	if x == nil {
+		return ""
+	}
+	return *x
+}
+
+func formatStat(x *float64) string {
+	if x == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", *x)
+}
@@ -3,444 +3,206 @@
 package main
 
 import (
-	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
-)
-
-// Data structures for taxonomy
-type Taxonomy struct {
-	Domains []Domain `json:"domains"`
-}
-
-type Domain struct {
-	ID                    int        `json:"id"`
-	Name                  string     `json:"name"`
-	Description           string     `json:"description"`
-	AbstractionScore      int        `json:"abstractionScore"`
-	EstimatedAgiWave      any        `json:"estimatedAgiWave"` // can be int or []int
-	PrimaryAISystemType   string     `json:"primaryAiSystemType"`
-	Categories            []Category `json:"categories"`
-}
-
-type Category struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-}
-
-// Index file structure (v2.0.0 data model)
-type IndexFile struct {
-	IndexID     string            `json:"indexId"`
-	IndexName   string            `json:"indexName"`
-	Description string            `json:"description"`
-	Version     string            `json:"version"`
-	Scale       IndexScale        `json:"scale"`
-	Values      map[string]int    `json:"values"`
-}
-
-type IndexScale struct {
-	Min    int          `json:"min"`
-	Max    int          `json:"max"`
-	Levels []IndexLevel `json:"levels"`
-}
-
-type IndexLevel struct {
-	Level      int    `json:"level"`
-	Name       string `json:"name"`
-	Definition string `json:"definition"`
-}
-
-// Activities file structure (v2.0.0)
-type ActivitiesFile struct {
-	Version         string     `json:"version"`
-	ActivitiesCount int        `json:"activitiesCount"`
-	Activities      []Activity `json:"activities"`
-}
-
-// Assessment file structure
-type AssessmentFile struct {
-	AssessmentDate      string                     `json:"assessmentDate"`
-	Version             string                     `json:"version"`
-	AGIWaveTimelines    AGIWaveTimelines           `json:"agiWaveTimelines"`
-	ActivityAssessments map[string]json.RawMessage `json:"activityAssessments"`
-}
-
-type AGIWaveTimelines struct {
-	Description string     `json:"description"`
-	Waves       []WaveInfo `json:"waves"`
-}
-
-type WaveInfo struct {
-	Wave            int      `json:"wave"`
-	Timeline        string   `json:"timeline"`
-	Characteristics string   `json:"characteristics"`
-	TypicalDomains  []string `json:"typicalDomains"`
-	KeyCapabilities []string `json:"keyCapabilities"`
-}
-
-type ActivityAssessment struct {
-	AICapability string `json:"aiCapability"`
-	Bottleneck   string `json:"bottleneck"`
-	AGIWave      int    `json:"agiWave"`
-}
-
-// Activity from domain files
-type DomainFile struct {
-	DomainID   int        `json:"domainId"`
-	DomainName string     `json:"domainName"`
-	Activities []Activity `json:"activities"`
-}
-
-type Activity struct {
-	ID           string   `json:"id"`
-	Name         string   `json:"name"`
-	Description  string   `json:"description"`
-	DomainID     int      `json:"domainId"`
-	CategoryID   string   `json:"categoryId"`
-	Scores       Scores   `json:"scores"`
-	ExampleTasks []string `json:"exampleTasks"`
-}
-
-type Scores struct {
-	Abstraction      int    `json:"abstraction"`
-	ErrorTolerance   int    `json:"errorTolerance"`
-	FeedbackSpeed    int    `json:"feedbackSpeed"`
-	InterpersonalComplexity int    `json:"interpersonalComplexity"`
-	Purpose          int    `json:"purpose"`
-	AICapability     string `json:"aiCapability"`
-	Bottleneck       string `json:"bottleneck"`
-	AGIWave          int    `json:"agiWave"`
-}
-
-// Mappings data
-type Mappings struct {
-	ATUSMapping    ATUSMapping    `json:"atusMapping"`
-	EconomicImpact EconomicImpact `json:"economicImpact"`
-}
-
-type ATUSMapping struct {
-	DataSource string        `json:"dataSource"`
-	TimeUnit   string        `json:"timeUnit"`
-	Mappings   []ATUSEntry   `json:"mappings"`
-	Summary    ATUSSummary   `json:"summary"`
-}
-
-type ATUSEntry struct {
-	ATUSCode          string             `json:"atusCode"`
-	ATUSCategory      string             `json:"atusCategory"`
-	HAICategories     []string           `json:"haaiCategories"`
-	Notes             string             `json:"notes"`
-	AvgMinutesPerDay  int                `json:"avgMinutesPerDay"`
-	ParticipationRate float64            `json:"participationRate"`
-	Breakdown         map[string]int     `json:"breakdown,omitempty"`
-}
-
-type ATUSSummary struct {
-	TotalMinutesPerDay   int `json:"totalMinutesPerDay"`
-	SleepAndPersonalCare int `json:"sleepAndPersonalCare"`
-	Work                 int `json:"work"`
-	Leisure              int `json:"leisure"`
-	HouseholdAndCare     int `json:"householdAndCare"`
-	Travel               int `json:"travel"`
-	Other                int `json:"other"`
-}
-
-type EconomicImpact struct {
-	Description   string           `json:"description"`
-	Currency      string           `json:"currency"`
-	Year          int              `json:"year"`
-	USLaborMarket USLaborMarket    `json:"usLaborMarket"`
-	DomainEcon    []DomainEconomic `json:"domainEconomics"`
-}
-
-type USLaborMarket struct {
-	TotalEmployment   int     `json:"totalEmployment"`
-	TotalWages        int64   `json:"totalWages"`
-	AverageHourlyWage float64 `json:"averageHourlyWage"`
-}
-
-type DomainEconomic struct {
-	DomainID            int      `json:"domainId"`
-	DomainName          string   `json:"domainName"`
-	EstimatedWorkers    int      `json:"estimatedWorkers"`
-	PercentOfWorkforce  float64  `json:"percentOfWorkforce"`
-	MedianHourlyWage    *float64 `json:"medianHourlyWage"`
-	AnnualValueBillions int      `json:"annualValueBillions"`
-	AutomationExposure  string   `json:"automationExposure"`
-	SampleOccupations   []string `json:"sampleOccupations"`
-	Notes               string   `json:"notes"`
-}
 
-var basePath string
+	"github.com/cederikdotcom/haai"
+)
 
-func init() {
-	// Find the base path (where taxonomy.json is)
+// findDataDir locates the directory containing taxonomy.json
+func findDataDir() string {
 	exe, err := os.Executable()
 	if err == nil {
 		// Try relative to executable
 		dir := filepath.Dir(exe)
 		if _, err := os.Stat(filepath.Join(dir, "..", "..", "taxonomy.json")); err == nil {
-			basePath = filepath.Join(dir, "..", "..")
-			return
+			return filepath.Join(dir, "..", "..")
 		}
 	}
 	// Try current directory
 	if _, err := os.Stat("taxonomy.json"); err == nil {
-		basePath = "."
-		return
+		return "."
 	}
 	// Try parent directories
 	cwd, _ := os.Getwd()
 	for dir := cwd; dir != "/"; dir = filepath.Dir(dir) {
 		if _, err := os.Stat(filepath.Join(dir, "taxonomy.json")); err == nil {
-			basePath = dir
-			return
+			return dir
 		}
 	}
-	basePath = "."
-}
-
-func loadJSON(filename string, v any) error {
-	path := filepath.Join(basePath, filename)
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return fmt.Errorf("reading %s: %w", path, err)
-	}
-	return json.Unmarshal(data, v)
-}
-
-func loadTaxonomy() (*Taxonomy, error) {
-	var t Taxonomy
-	if err := loadJSON("taxonomy.json", &t); err != nil {
-		return nil, err
-	}
-	return &t, nil
+	return "."
 }
 
-func loadActivities() ([]Activity, error) {
-	// First try to load from new activities.json format
-	var af ActivitiesFile
-	if err := loadJSON("activities.json", &af); err == nil && len(af.Activities) > 0 {
-		// Successfully loaded v2.0.0 format
-		activities := af.Activities
-
-		// Load indices and assessments to populate scores
-		indices, _ := loadIndices()
-		assessment, _ := loadLatestAssessment()
-
-		// Load intrinsic scores (feedbackSpeed, interpersonalComplexity) from domain files
-		feedbackMap, interpersonalMap := loadIntrinsicScoresFromDomainFiles()
-
-		// Merge scores into activities
-		mergeScores(activities, indices, assessment, feedbackMap, interpersonalMap)
-
-		return activities, nil
-	}
-
-	// Fall back to loading directly from domain-X.json files
-	var all []Activity
-	for i := 1; i <= 10; i++ {
-		var df DomainFile
-		filename := fmt.Sprintf("activities/domain-%d.json", i)
-		if err := loadJSON(filename, &df); err != nil {
-			continue // Skip missing files
-		}
-		// Set DomainID for each activity from the domain file
-		for j := range df.Activities {
-			df.Activities[j].DomainID = df.DomainID
-		}
-		all = append(all, df.Activities...)
-	}
-
-	// Load time-dependent assessments (aiCapability, bottleneck, agiWave)
-	assessment, _ := loadLatestAssessment()
-	if assessment != nil {
-		for i := range all {
-			id := all[i].ID
-			if raw, ok := assessment.ActivityAssessments[id]; ok {
-				var aa ActivityAssessment
-				if err := json.Unmarshal(raw, &aa); err == nil {
-					all[i].Scores.AICapability = aa.AICapability
-					all[i].Scores.Bottleneck = aa.Bottleneck
-					all[i].Scores.AGIWave = aa.AGIWave
-				}
+// globalOptions removes options that apply to every command (--as-of,
+// --format) from the argument list and returns the remaining arguments and
+// load options
+func globalOptions(args []string) ([]string, []haai.Option, error) {
+	var rest []string
+	var opts []haai.Option
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--as-of" || arg == "-as-of":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("--as-of requires a date (YYYY-MM-DD)")
 			}
-		}
-	}
-
-	return all, nil
-}
-
-// loadIndices loads abstraction.json, error-tolerance.json, and purpose.json from indices/
-func loadIndices() (map[string]*IndexFile, error) {
-	indices := make(map[string]*IndexFile)
-	indexNames := []string{"abstraction", "error-tolerance", "purpose"}
-
-	for _, name := range indexNames {
-		var idx IndexFile
-		filename := fmt.Sprintf("indices/%s.json", name)
-		if err := loadJSON(filename, &idx); err != nil {
-			continue // Skip missing files
-		}
-		indices[name] = &idx
-	}
-
-	return indices, nil
-}
-
-// loadLatestAssessment finds and loads the most recent assessment file from assessments/
-func loadLatestAssessment() (*AssessmentFile, error) {
-	assessmentsDir := filepath.Join(basePath, "assessments")
-	entries, err := os.ReadDir(assessmentsDir)
-	if err != nil {
-		return nil, err
-	}
-
-	// Find the most recent assessment file (sorted by name = date)
-	var latestFile string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
-			if entry.Name() > latestFile {
-				latestFile = entry.Name()
+			i++
+			opts = append(opts, haai.AsOf(args[i]))
+		case strings.HasPrefix(arg, "--as-of="):
+			opts = append(opts, haai.AsOf(strings.TrimPrefix(arg, "--as-of=")))
+		case arg == "--format" || arg == "-format":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("--format requires one of %s", strings.Join(outputFormats, ", "))
 			}
+			i++
+			outputFormat = args[i]
+		case strings.HasPrefix(arg, "--format="):
+			outputFormat = strings.TrimPrefix(arg, "--format=")
+		default:
+			rest = append(rest, arg)
 		}
 	}
-
-	if latestFile == "" {
-		return nil, fmt.Errorf("no assessment files found")
-	}
-
-	var af AssessmentFile
-	if err := loadJSON("assessments/"+latestFile, &af); err != nil {
-		return nil, err
-	}
-
-	return &af, nil
-}
-
-// loadIntrinsicScoresFromDomainFiles loads feedbackSpeed and interpersonalComplexity
-// which remain embedded in domain activity files as intrinsic properties
-func loadIntrinsicScoresFromDomainFiles() (map[string]int, map[string]int) {
-	feedbackMap := make(map[string]int)
-	interpersonalMap := make(map[string]int)
-
-	for i := 1; i <= 10; i++ {
-		var df DomainFile
-		filename := fmt.Sprintf("activities/domain-%d.json", i)
-		if err := loadJSON(filename, &df); err != nil {
-			continue
-		}
-		for _, a := range df.Activities {
-			feedbackMap[a.ID] = a.Scores.FeedbackSpeed
-			interpersonalMap[a.ID] = a.Scores.InterpersonalComplexity
+	for _, f := range outputFormats {
+		if outputFormat == f {
+			return rest, opts, nil
 		}
 	}
-
-	return feedbackMap, interpersonalMap
+	return nil, nil, fmt.Errorf("unknown format %q (use %s)", outputFormat, strings.Join(outputFormats, ", "))
 }
 
-// mergeScores merges all score data into activity Scores structs:
-// - Intrinsic indices (abstraction, error-tolerance, purpose) from indices/
-// - Intrinsic scores (feedbackSpeed, interpersonalComplexity) from domain files
-// - Time-dependent assessments (aiCapability, bottleneck, agiWave) from assessments/
-func mergeScores(activities []Activity, indices map[string]*IndexFile, assessment *AssessmentFile, feedbackMap, interpersonalMap map[string]int) {
-	for i := range activities {
-		id := activities[i].ID
-
-		// Intrinsic indices from indices/
-		if idx, ok := indices["abstraction"]; ok {
-			if val, ok := idx.Values[id]; ok {
-				activities[i].Scores.Abstraction = val
-			}
-		}
-		if idx, ok := indices["error-tolerance"]; ok {
-			if val, ok := idx.Values[id]; ok {
-				activities[i].Scores.ErrorTolerance = val
-			}
-		}
-		if idx, ok := indices["purpose"]; ok {
-			if val, ok := idx.Values[id]; ok {
-				activities[i].Scores.Purpose = val
-			}
-		}
-
-		// Intrinsic scores from domain activity files
-		if val, ok := feedbackMap[id]; ok {
-			activities[i].Scores.FeedbackSpeed = val
-		}
-		if val, ok := interpersonalMap[id]; ok {
-			activities[i].Scores.InterpersonalComplexity = val
-		}
-
-		// Time-dependent assessments from assessments/ (these change as AI evolves)
-		if assessment != nil {
-			if raw, ok := assessment.ActivityAssessments[id]; ok {
-				var aa ActivityAssessment
-				if err := json.Unmarshal(raw, &aa); err == nil {
-					activities[i].Scores.AICapability = aa.AICapability
-					activities[i].Scores.Bottleneck = aa.Bottleneck
-					activities[i].Scores.AGIWave = aa.AGIWave
-				}
-			}
-		}
+// printAssessmentSource shows which assessment snapshot the time-dependent scores come from
+func printAssessmentSource(ds *haai.Dataset) {
+	if f := ds.AssessmentFile(); f != "" {
+		fmt.Printf("Assessment: %s\n", f)
+	} else {
+		fmt.Println("Assessment: none")
 	}
 }
 
-func loadMappings() (*Mappings, error) {
-	var m Mappings
-	if err := loadJSON("mappings.json", &m); err != nil {
-		return nil, err
+// parseFlags parses flags that may appear before, between or after
+// positional arguments and returns the positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
 	}
-	return &m, nil
 }
 
 func printUsage() {
 	fmt.Println(`haai - Human Activity Automation Index CLI
 
 Usage:
-  haai <command> [arguments]
+  haai [--as-of YYYY-MM-DD] <command> [arguments]
+
+Global options:
+  --as-of YYYY-MM-DD   Use the newest assessment dated on or before this date
+  --format <format>    Output format: table (default), json, csv, tsv, yaml or markdown
 
 Commands:
   domains              List all 10 domains with abstraction scores
-  domain <id>          Show domain details and its categories
+  domain <id>          Show domain details, its categories and their ATUS time share (--atus-weights)
   activities [domain]  List activities (optionally filter by domain ID)
   activity <id>        Show activity details (e.g., "3.3.1")
+  history <id>         Show an activity's capability timeline across all assessments
   wave <n>             List activities by AGI wave (1-4)
   capability <status>  List by AI capability (solved, near_solved, partial, early, not_attempted)
   bottleneck <type>    List by bottleneck (dexterity, social, reasoning, mobility, etc.)
-  index <name>         Show details about an index (abstraction, error-tolerance, or purpose)
+  index <name>         Show details about an index (e.g. abstraction, feedback-speed, purpose)
   purpose <level>      List activities by purpose level (1-5)
-  search <term>        Search activities by name or description
+  search <terms>       Ranked search over names, descriptions, example tasks and taxonomy text (--limit)
   time                 Show ATUS time-spent data
   econ                 Show economic impact by domain
-  stats                Show summary statistics
+  econ exposure        Workers and value exposed per wave from the activity assessments vs the projections (--tolerance)
+  stats                Show summary statistics with activity counts and ATUS time shares (--atus-weights)
+  classify <text>      Suggest categories for a new activity and the boundary rules between them (--limit)
+  validate [flags]     Run the validation.json suites and record passing ones (--dry-run, --verbose, --date)
+  irr sample [flags]   Export a blinded rating sheet of random activities (--n, --seed, --out)
+  irr score <sheets>   Inter-rater agreement (Cohen/Fleiss kappa, Krippendorff alpha) and disputed items
+  calibrate [flags]    Score wave predictions against deployments.json (--level, --horizon, --today)
+  onet                 Show HAAI automation exposure per O*NET work activity
+  onet import <file>   Per-occupation exposure from an O*NET Work Activities or Task Statements file (--top)
+  occupations          Rank the ISCO-08 major groups by automation exposure (--weights)
+  occupation isco <n>  Show an ISCO-08 group's capability, wave and readiness profile (--weights)
+  benchmark <name>     Benchmark categories with their HAAI activities and unmeasured physical categories (behavior1k, activitynet)
+  xref <system> [code] List a system's codes, or the activities a code maps to (onet, atus, behavior1k, activitynet, isco)
+  day [diary.json]     Minutes of a day by AI capability and AGI wave (default: ATUS population average)
+  simulate [flags]     Year-by-year automated share, time use and value per adoption scenario (--scenarios, --scenario, --from, --to)
+  forecast [domain]    P10/P50/P90 automation dates by Monte Carlo (--samples, --seed, --share, --spreads, --open-years)
+  query <expr> [flags] Filter activities with an expression (--sort, --fields, --limit)
+  rank [flags]         Rank activities by automation readiness (--domain, --category, --limit, --asc)
+  essential [flags]    List the most human-essential activities and the factors that apply (--domain, --limit)
+  diff <dateA> <dateB> Compare two assessment snapshots
+  assessment new       Scaffold a new snapshot from the latest one (--date, --message)
+  assessment set <id> capability=.. bottleneck=.. wave=..
+                       Update an activity's entry in the current snapshot (--date)
+  lint                 Check data files for consistency (exits 1 on issues)
 
 Examples:
   haai domains
   haai domain 3
   haai activities 1
   haai activity 3.3.1
+  haai history 9.1.1
   haai wave 1
   haai capability solved
   haai bottleneck dexterity
   haai index purpose
   haai purpose 1
   haai search "code"
+  haai search wiping surfaces
   haai time
   haai econ
-  haai stats`)
+  haai econ exposure
+  haai stats
+  haai stats --atus-weights atus-weights.json
+  haai classify "negotiating a lease renewal with a tenant"
+  haai query "domain in (4,5) and abstraction<=2 and capability!=solved and wave>=3"
+  haai query "purpose=4 or bottleneck=social" --sort errorTolerance desc --fields id,name,errorTolerance
+  haai rank --domain 4 --limit 10
+  haai essential --limit 20
+  haai diff 2026-01-01 2026-04-01
+  haai assessment new --date 2026-04-01
+  haai assessment set 4.1.1 capability=solved wave=1
+  haai lint
+  haai validate --dry-run
+  haai irr sample --n 50 --seed 7 --out raterA.json
+  haai irr score raterA.json raterB.json
+  haai calibrate --level near_solved
+  haai onet import "Work Activities.txt" --top 20
+  haai occupations
+  haai occupation isco 4 --weights 1.2=0.5,2.3=0.2,5.1=0.3
+  haai benchmark behavior1k
+  haai xref atus 02
+  haai xref onet "Thinking Creatively"
+  haai day
+  haai day validation.json
+  haai simulate --to 2040
+  haai simulate --scenario fast,slow --format csv
+  haai forecast --seed 7
+  haai forecast 9
+  haai --as-of 2026-03-31 stats
+  haai --format json activities 4
+  haai --format csv table > activities.csv`)
 }
 
-func cmdDomains() {
-	tax, err := loadTaxonomy()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+func cmdDomains(ds *haai.Dataset) {
+	if machineOutput() {
+		t := newTabular("id", "name", "abstractionScore", "estimatedAgiWave", "primaryAiSystemType", "categories")
+		for _, d := range ds.Domains() {
+			t.add(d.ID, d.Name, d.AbstractionScore, formatWave(d.EstimatedAgiWave), d.PrimaryAISystemType, len(d.Categories))
+		}
+		emit(ds.Domains(), t)
+		return
 	}
 
 	fmt.Println("HAAI Domains (ordered by abstraction level)")
@@ -448,7 +210,7 @@ func cmdDomains() {
 	fmt.Printf("%-4s %-35s %-6s %-8s\n", "ID", "Domain", "Abstr", "AGI Wave")
 	fmt.Println(strings.Repeat("-", 70))
 
-	for _, d := range tax.Domains {
+	for _, d := range ds.Domains() {
 		wave := formatWave(d.EstimatedAgiWave)
 		fmt.Printf("%-4d %-35s %-6d %-8s\n", d.ID, d.Name, d.AbstractionScore, wave)
 	}
@@ -471,24 +233,70 @@ func formatWave(w any) string {
 	}
 }
 
-func cmdDomain(id int) {
-	tax, err := loadTaxonomy()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+func cmdDomain(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("domain", flag.ExitOnError)
+	weightsFile := fs.String("atus-weights", "", "JSON file of HAAI weights per ATUS code for the time shares")
+	rest := parseFlags(fs, args)
+	if len(rest) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: haai domain <id> [--atus-weights file]")
 		os.Exit(1)
 	}
-
-	var domain *Domain
-	for _, d := range tax.Domains {
-		if d.ID == id {
-			domain = &d
-			break
-		}
+	id, err := strconv.Atoi(rest[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid domain ID: %s\n", rest[0])
+		os.Exit(1)
 	}
-	if domain == nil {
+	domain, ok := ds.Domain(id)
+	if !ok {
 		fmt.Fprintf(os.Stderr, "Domain %d not found\n", id)
 		os.Exit(1)
 	}
+	alloc := allocateATUS(ds, *weightsFile)
+	counts := make(map[string]int)
+	for _, a := range ds.Activities() {
+		counts[a.CategoryID]++
+	}
+	total := 0
+	for _, c := range domain.Categories {
+		total += counts[c.ID]
+	}
+	share := 0.0
+	if allocated := alloc.TotalMinutes - alloc.Unallocated; allocated > 0 {
+		share = alloc.Domains[domain.ID] / allocated
+	}
+	if machineOutput() {
+		byCategory := make(map[string][]haai.ExternalRef)
+		for _, a := range ds.Activities() {
+			if a.DomainID == domain.ID {
+				byCategory[a.CategoryID] = mergeExternalRefs(byCategory[a.CategoryID], ds.ExternalRefs(a.ID))
+			}
+		}
+		report := domainReport{
+			ID:                  domain.ID,
+			Name:                domain.Name,
+			Description:         domain.Description,
+			AbstractionScore:    domain.AbstractionScore,
+			EstimatedAgiWave:    domain.EstimatedAgiWave,
+			PrimaryAISystemType: domain.PrimaryAISystemType,
+			Activities:          total,
+			ATUSMinutes:         alloc.Domains[domain.ID],
+			ATUSShare:           share,
+			ExternalMappings:    nonNilRefs(ds.DomainExternalRefs(domain.ID)),
+		}
+		t := newTabular("id", "name", "description", "activities", "atusMinutes", "externalMappings")
+		for _, c := range domain.Categories {
+			report.Categories = append(report.Categories, domainCategoryReport{
+				ID:          c.ID,
+				Name:        c.Name,
+				Description: c.Description,
+				Activities:  counts[c.ID],
+				ATUSMinutes: alloc.Categories[c.ID],
+			})
+			t.add(c.ID, c.Name, c.Description, counts[c.ID], alloc.Categories[c.ID], formatExternalRefs(byCategory[c.ID]))
+		}
+		emit(report, t)
+		return
+	}
 
 	fmt.Printf("Domain %d: %s\n", domain.ID, domain.Name)
 	fmt.Println(strings.Repeat("-", 60))
@@ -496,19 +304,61 @@ func cmdDomain(id int) {
 	fmt.Printf("Abstraction:     %d/10\n", domain.AbstractionScore)
 	fmt.Printf("AGI Wave:        %s\n", formatWave(domain.EstimatedAgiWave))
 	fmt.Printf("Primary AI:      %s\n", domain.PrimaryAISystemType)
+	fmt.Printf("Activities:      %d (%.1f%% of all activities)\n", total, float64(total)/float64(len(ds.Activities()))*100)
+	fmt.Printf("ATUS Time:       %.0f min/day (%.1f%% of the allocated day)\n", alloc.Domains[domain.ID], share*100)
 	fmt.Println()
 	fmt.Println("Categories:")
 	for _, c := range domain.Categories {
-		fmt.Printf("  %s: %s\n", c.ID, c.Name)
+		fmt.Printf("  %s: %s (%d activities, %.0f min/day)\n", c.ID, c.Name, counts[c.ID], alloc.Categories[c.ID])
 		fmt.Printf("      %s\n", c.Description)
 	}
+	if refs := ds.DomainExternalRefs(domain.ID); len(refs) > 0 {
+		fmt.Println()
+		printExternalRefs(ds, refs)
+	}
 }
 
-func cmdActivities(domainFilter int) {
-	activities, err := loadActivities()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+// domainReport is the machine-readable form of cmdDomain: the domain with
+// its activity counts and ATUS time per category
+type domainReport struct {
+	ID                  int                    `json:"id"`
+	Name                string                 `json:"name"`
+	Description         string                 `json:"description"`
+	AbstractionScore    int                    `json:"abstractionScore"`
+	EstimatedAgiWave    any                    `json:"estimatedAgiWave"`
+	PrimaryAISystemType string                 `json:"primaryAiSystemType"`
+	Activities          int                    `json:"activities"`
+	ATUSMinutes         float64                `json:"atusMinutes"`
+	ATUSShare           float64                `json:"atusShare"` // of the minutes allocated to activities
+	Categories          []domainCategoryReport `json:"categories"`
+	ExternalMappings    []haai.ExternalRef     `json:"externalMappings"`
+}
+
+type domainCategoryReport struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Activities  int     `json:"activities"`
+	ATUSMinutes float64 `json:"atusMinutes"`
+}
+
+// activityReport is the machine-readable form of cmdActivity
+type activityReport struct {
+	haai.Activity
+	ExternalMappings []haai.ExternalRef `json:"externalMappings"`
+}
+
+func cmdActivities(ds *haai.Dataset, domainFilter int) {
+	activities := ds.Activities()
+	if machineOutput() {
+		var selected []haai.Activity
+		for _, a := range activities {
+			if domainFilter == 0 || haai.DomainFromID(a.ID) == domainFilter {
+				selected = append(selected, a)
+			}
+		}
+		emitActivities(ds, selected)
+		return
 	}
 
 	if domainFilter > 0 {
@@ -516,12 +366,13 @@ func cmdActivities(domainFilter int) {
 	} else {
 		fmt.Println("All Activities")
 	}
+	printAssessmentSource(ds)
 	fmt.Println(strings.Repeat("-", 80))
 	fmt.Printf("%-8s %-40s %-12s %-5s\n", "ID", "Name", "Capability", "Wave")
 	fmt.Println(strings.Repeat("-", 80))
 
 	for _, a := range activities {
-		domainID := getDomainFromID(a.ID)
+		domainID := haai.DomainFromID(a.ID)
 		if domainFilter > 0 && domainID != domainFilter {
 			continue
 		}
@@ -533,35 +384,23 @@ func cmdActivities(domainFilter int) {
 	}
 }
 
-func getDomainFromID(id string) int {
-	parts := strings.Split(id, ".")
-	if len(parts) > 0 {
-		n, _ := strconv.Atoi(parts[0])
-		return n
-	}
-	return 0
-}
-
-func cmdActivity(id string) {
-	activities, err := loadActivities()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
-
-	var activity *Activity
-	for _, a := range activities {
-		if a.ID == id {
-			activity = &a
-			break
-		}
-	}
-	if activity == nil {
+func cmdActivity(ds *haai.Dataset, id string) {
+	activity, ok := ds.Activity(id)
+	if !ok {
 		fmt.Fprintf(os.Stderr, "Activity %s not found\n", id)
 		os.Exit(1)
 	}
+	if machineOutput() {
+		refs := ds.ExternalRefs(activity.ID)
+		t := activityTable(ds, []haai.Activity{*activity})
+		t.headers = append(t.headers, "externalMappings")
+		t.rows[0] = append(t.rows[0], formatExternalRefs(refs))
+		emit(activityReport{Activity: *activity, ExternalMappings: nonNilRefs(refs)}, t)
+		return
+	}
 
 	fmt.Printf("Activity %s: %s\n", activity.ID, activity.Name)
+	printAssessmentSource(ds)
 	fmt.Println(strings.Repeat("-", 60))
 	fmt.Printf("Description:  %s\n", activity.Description)
 	fmt.Printf("Category:     %s\n", activity.CategoryID)
@@ -579,12 +418,31 @@ func cmdActivity(id string) {
 	fmt.Printf("  Bottleneck:        %s\n", activity.Scores.Bottleneck)
 	fmt.Printf("  AGI Wave:          %d\n", activity.Scores.AGIWave)
 	fmt.Println()
+	fmt.Println("Composite Scores:")
+	if ds.CompositeWeights() != nil {
+		readiness := activity.Scores.AutomationReadiness
+		if band, ok := ds.ReadinessBand(readiness); ok {
+			fmt.Printf("  Automation Readiness: %.2f (%s)\n", readiness, band.Label)
+		} else {
+			fmt.Printf("  Automation Readiness: %.2f\n", readiness)
+		}
+	}
+	if len(ds.EssentialityFactors()) > 0 {
+		e := ds.HumanEssentiality(activity)
+		fmt.Printf("  Human Essentiality:   %g/%g\n", e.Score, e.Max)
+		for _, f := range e.Factors {
+			fmt.Printf("    - %s: %s\n", f.ID, f.Description)
+		}
+	}
+	fmt.Println()
 	if len(activity.ExampleTasks) > 0 {
 		fmt.Println("Example Tasks:")
 		for _, t := range activity.ExampleTasks {
 			fmt.Printf("  - %s\n", t)
 		}
+		fmt.Println()
 	}
+	printExternalRefs(ds, ds.ExternalRefs(activity.ID))
 }
 
 // getPurposeName returns the name for a purpose level
@@ -603,23 +461,19 @@ func getPurposeName(level int) string {
 }
 
 // cmdTable shows all activities with index values, grouped by domain
-func cmdTable() {
-	activities, err := loadActivities()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+func cmdTable(ds *haai.Dataset) {
+	activities := ds.Activities()
+	if machineOutput() {
+		emitActivities(ds, activities)
+		return
 	}
 
-	tax, err := loadTaxonomy()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
+	printAssessmentSource(ds)
 
 	// Group activities by domain
-	byDomain := make(map[int][]Activity)
+	byDomain := make(map[int][]haai.Activity)
 	for _, a := range activities {
-		domainID := getDomainFromID(a.ID)
+		domainID := haai.DomainFromID(a.ID)
 		byDomain[domainID] = append(byDomain[domainID], a)
 	}
 
@@ -632,7 +486,7 @@ func cmdTable() {
 
 		// Find domain name
 		domainName := fmt.Sprintf("Domain %d", domainID)
-		for _, d := range tax.Domains {
+		for _, d := range ds.Domains() {
 			if d.ID == domainID {
 				domainName = d.Name
 				break
@@ -658,21 +512,27 @@ func cmdTable() {
 	fmt.Println()
 }
 
-func cmdWave(wave int) {
-	activities, err := loadActivities()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+func cmdWave(ds *haai.Dataset, wave int) {
+	activities := ds.Activities()
+	if machineOutput() {
+		var selected []haai.Activity
+		for _, a := range activities {
+			if a.Scores.AGIWave == wave {
+				selected = append(selected, a)
+			}
+		}
+		emitActivities(ds, selected)
+		return
 	}
 
-	timelines := map[int]string{
-		1: "2024-2026",
-		2: "2026-2028",
-		3: "2028-2032",
-		4: "2032+",
+	title := fmt.Sprintf("AGI Wave %d Activities", wave)
+	if a := ds.Assessment(); a != nil {
+		if w, ok := a.WaveWindows()[wave]; ok {
+			title += fmt.Sprintf(" (%s)", w)
+		}
 	}
-
-	fmt.Printf("AGI Wave %d Activities (%s)\n", wave, timelines[wave])
+	fmt.Println(title)
+	printAssessmentSource(ds)
 	fmt.Println(strings.Repeat("-", 80))
 	fmt.Printf("%-8s %-40s %-12s %-10s\n", "ID", "Name", "Capability", "Bottleneck")
 	fmt.Println(strings.Repeat("-", 80))
@@ -691,14 +551,21 @@ func cmdWave(wave int) {
 	fmt.Printf("\nTotal: %d activities\n", count)
 }
 
-func cmdCapability(status string) {
-	activities, err := loadActivities()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+func cmdCapability(ds *haai.Dataset, status string) {
+	activities := ds.Activities()
+	if machineOutput() {
+		var selected []haai.Activity
+		for _, a := range activities {
+			if a.Scores.AICapability == status {
+				selected = append(selected, a)
+			}
+		}
+		emitActivities(ds, selected)
+		return
 	}
 
 	fmt.Printf("Activities with AI Capability: %s\n", status)
+	printAssessmentSource(ds)
 	fmt.Println(strings.Repeat("-", 80))
 	fmt.Printf("%-8s %-40s %-10s %-5s\n", "ID", "Name", "Bottleneck", "Wave")
 	fmt.Println(strings.Repeat("-", 80))
@@ -717,14 +584,21 @@ func cmdCapability(status string) {
 	fmt.Printf("\nTotal: %d activities\n", count)
 }
 
-func cmdBottleneck(bottleneck string) {
-	activities, err := loadActivities()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+func cmdBottleneck(ds *haai.Dataset, bottleneck string) {
+	activities := ds.Activities()
+	if machineOutput() {
+		var selected []haai.Activity
+		for _, a := range activities {
+			if a.Scores.Bottleneck == bottleneck {
+				selected = append(selected, a)
+			}
+		}
+		emitActivities(ds, selected)
+		return
 	}
 
 	fmt.Printf("Activities with Bottleneck: %s\n", bottleneck)
+	printAssessmentSource(ds)
 	fmt.Println(strings.Repeat("-", 80))
 	fmt.Printf("%-8s %-40s %-12s %-5s\n", "ID", "Name", "Capability", "Wave")
 	fmt.Println(strings.Repeat("-", 80))
@@ -743,42 +617,17 @@ func cmdBottleneck(bottleneck string) {
 	fmt.Printf("\nTotal: %d activities\n", count)
 }
 
-func cmdSearch(term string) {
-	activities, err := loadActivities()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
-
-	term = strings.ToLower(term)
-	fmt.Printf("Search results for: %s\n", term)
-	fmt.Println(strings.Repeat("-", 80))
-	fmt.Printf("%-8s %-40s %-12s %-5s\n", "ID", "Name", "Capability", "Wave")
-	fmt.Println(strings.Repeat("-", 80))
-
-	count := 0
-	for _, a := range activities {
-		if strings.Contains(strings.ToLower(a.Name), term) ||
-			strings.Contains(strings.ToLower(a.Description), term) {
-			name := a.Name
-			if len(name) > 40 {
-				name = name[:37] + "..."
-			}
-			fmt.Printf("%-8s %-40s %-12s %-5d\n", a.ID, name, a.Scores.AICapability, a.Scores.AGIWave)
-			count++
+func cmdTime(ds *haai.Dataset) {
+	mappings := ds.Mappings()
+	atus := mappings.ATUSMapping
+	if machineOutput() {
+		t := newTabular("atusCode", "atusCategory", "haaiCategories", "avgMinutesPerDay", "participationRate")
+		for _, e := range atus.Mappings {
+			t.add(e.ATUSCode, e.ATUSCategory, strings.Join(e.HAICategories, ","), e.AvgMinutesPerDay, e.ParticipationRate)
 		}
+		emit(atus, t)
+		return
 	}
-	fmt.Printf("\nTotal: %d activities\n", count)
-}
-
-func cmdTime() {
-	mappings, err := loadMappings()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
-
-	atus := mappings.ATUSMapping
 	fmt.Println("ATUS Time-Spent Data (Average Minutes Per Day)")
 	fmt.Printf("Source: %s\n", atus.DataSource)
 	fmt.Println(strings.Repeat("-", 75))
@@ -804,14 +653,21 @@ func cmdTime() {
 	fmt.Printf("  Total:                 %d min (24 hrs)\n", atus.Summary.TotalMinutesPerDay)
 }
 
-func cmdEcon() {
-	mappings, err := loadMappings()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+func cmdEcon(ds *haai.Dataset, args []string) {
+	if len(args) > 0 && args[0] == "exposure" {
+		cmdEconExposure(ds, args[1:])
+		return
 	}
-
+	mappings := ds.Mappings()
 	econ := mappings.EconomicImpact
+	if machineOutput() {
+		t := newTabular("domainId", "domainName", "estimatedWorkers", "percentOfWorkforce", "annualValueBillions", "automationExposure")
+		for _, d := range econ.DomainEcon {
+			t.add(d.DomainID, d.DomainName, d.EstimatedWorkers, d.PercentOfWorkforce, d.AnnualValueBillions, d.AutomationExposure)
+		}
+		emit(econ, t)
+		return
+	}
 	fmt.Printf("Economic Impact by HAAI Domain (%s %d)\n", econ.Currency, econ.Year)
 	fmt.Println(strings.Repeat("-", 90))
 	fmt.Printf("%-4s %-28s %12s %8s %12s %-12s\n", "ID", "Domain", "Workers", "% Work", "Value ($B)", "Automation")
@@ -847,48 +703,71 @@ func formatNumber(n int) string {
 	return fmt.Sprintf("%d", n)
 }
 
-func cmdStats() {
-	activities, err := loadActivities()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
+func cmdStats(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	weightsFile := fs.String("atus-weights", "", "JSON file of HAAI weights per ATUS code for the time shares")
+	parseFlags(fs, args)
+	alloc := allocateATUS(ds, *weightsFile)
+	activities := ds.Activities()
 
-	// Count by capability
+	// Count by capability, alongside the ATUS minutes per day of those activities
 	capCounts := make(map[string]int)
 	waveCounts := make(map[int]int)
 	bottleneckCounts := make(map[string]int)
 	domainCounts := make(map[int]int)
 	purposeCounts := make(map[int]int)
+	minutes := statsMinutes{
+		ByCapability: make(map[string]float64),
+		ByWave:       make(map[int]float64),
+		ByPurpose:    make(map[int]float64),
+		ByBottleneck: make(map[string]float64),
+		ByDomain:     alloc.Domains,
+	}
 
 	for _, a := range activities {
 		capCounts[a.Scores.AICapability]++
 		waveCounts[a.Scores.AGIWave]++
 		bottleneckCounts[a.Scores.Bottleneck]++
-		domainCounts[getDomainFromID(a.ID)]++
+		domainCounts[haai.DomainFromID(a.ID)]++
 		purposeCounts[a.Scores.Purpose]++
+
+		m := alloc.Activities[a.ID]
+		minutes.Total += m
+		minutes.ByCapability[a.Scores.AICapability] += m
+		minutes.ByWave[a.Scores.AGIWave] += m
+		minutes.ByBottleneck[a.Scores.Bottleneck] += m
+		minutes.ByPurpose[a.Scores.Purpose] += m
+	}
+	if machineOutput() {
+		emitStats(ds, len(activities), capCounts, waveCounts, bottleneckCounts, domainCounts, purposeCounts, minutes)
+		return
+	}
+	timeShare := func(m float64) string {
+		return fmt.Sprintf("%5.0f min (%5.1f%%)", m, m/minutes.Total*100)
 	}
 
 	fmt.Println("HAAI Taxonomy Statistics")
+	printAssessmentSource(ds)
 	fmt.Println(strings.Repeat("=", 50))
-	fmt.Printf("Total Activities: %d\n\n", len(activities))
+	fmt.Printf("Total Activities: %d\n", len(activities))
+	fmt.Printf("ATUS Time:        %.0f min/day allocated to activities\n\n", minutes.Total)
 
 	fmt.Println("By AI Capability:")
 	for _, cap := range []string{"solved", "near_solved", "partial", "early", "not_attempted"} {
 		pct := float64(capCounts[cap]) / float64(len(activities)) * 100
-		fmt.Printf("  %-15s %4d (%5.1f%%)\n", cap, capCounts[cap], pct)
+		fmt.Printf("  %-15s %4d (%5.1f%%)  %s\n", cap, capCounts[cap], pct, timeShare(minutes.ByCapability[cap]))
 	}
 
 	fmt.Println("\nBy AGI Wave:")
 	for wave := 1; wave <= 4; wave++ {
 		pct := float64(waveCounts[wave]) / float64(len(activities)) * 100
-		fmt.Printf("  Wave %d:         %4d (%5.1f%%)\n", wave, waveCounts[wave], pct)
+		fmt.Printf("  Wave %d:         %4d (%5.1f%%)  %s\n", wave, waveCounts[wave], pct, timeShare(minutes.ByWave[wave]))
 	}
 
 	fmt.Println("\nBy Purpose:")
 	for purpose := 1; purpose <= 5; purpose++ {
 		pct := float64(purposeCounts[purpose]) / float64(len(activities)) * 100
-		fmt.Printf("  %d %-13s %4d (%5.1f%%)\n", purpose, getPurposeName(purpose), purposeCounts[purpose], pct)
+		fmt.Printf("  %d %-13s %4d (%5.1f%%)  %s\n", purpose, getPurposeName(purpose), purposeCounts[purpose], pct, timeShare(minutes.ByPurpose[purpose]))
 	}
 
 	fmt.Println("\nBy Bottleneck:")
@@ -904,28 +783,125 @@ func cmdStats() {
 			continue
 		}
 		pct := float64(bottleneckCounts[b]) / float64(len(activities)) * 100
-		fmt.Printf("  %-12s %4d (%5.1f%%)\n", b, bottleneckCounts[b], pct)
+		fmt.Printf("  %-12s %4d (%5.1f%%)  %s\n", b, bottleneckCounts[b], pct, timeShare(minutes.ByBottleneck[b]))
 	}
 
 	fmt.Println("\nBy Domain:")
 	for d := 1; d <= 10; d++ {
-		fmt.Printf("  Domain %2d:     %4d activities\n", d, domainCounts[d])
+		fmt.Printf("  Domain %2d:     %4d activities  %s\n", d, domainCounts[d], timeShare(minutes.ByDomain[d]))
 	}
 }
 
-// cmdIndex shows details about an index (abstraction, error-tolerance, or purpose)
-func cmdIndex(name string) {
-	indices, err := loadIndices()
+// allocateATUS allocates the ATUS average day to activities, with optional
+// weights from a file
+func allocateATUS(ds *haai.Dataset, weightsFile string) *haai.ATUSAllocation {
+	var weights haai.ATUSWeights
+	if weightsFile != "" {
+		var err error
+		if weights, err = haai.ReadATUSWeights(weightsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	alloc, err := ds.AllocateATUS(weights)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading indices: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	return alloc
+}
+
+// statsMinutes is the ATUS time share behind cmdStats' counts
+type statsMinutes struct {
+	Total        float64            `json:"total"`
+	ByCapability map[string]float64 `json:"byCapability"`
+	ByWave       map[int]float64    `json:"byWave"`
+	ByPurpose    map[int]float64    `json:"byPurpose"`
+	ByBottleneck map[string]float64 `json:"byBottleneck"`
+	ByDomain     map[int]float64    `json:"byDomain"`
+}
+
+// statsReport is the machine-readable form of cmdStats
+type statsReport struct {
+	Assessment   string         `json:"assessment"`
+	Total        int            `json:"total"`
+	ByCapability map[string]int `json:"byCapability"`
+	ByWave       map[int]int    `json:"byWave"`
+	ByPurpose    map[int]int    `json:"byPurpose"`
+	ByBottleneck map[string]int `json:"byBottleneck"`
+	ByDomain     map[int]int    `json:"byDomain"`
+	Minutes      statsMinutes   `json:"atusMinutes"`
+}
+
+func emitStats(ds *haai.Dataset, total int, capCounts map[string]int, waveCounts map[int]int,
+	bottleneckCounts map[string]int, domainCounts, purposeCounts map[int]int, minutes statsMinutes) {
+	delete(bottleneckCounts, "")
+	delete(minutes.ByBottleneck, "")
+	report := statsReport{
+		Assessment:   ds.AssessmentFile(),
+		Total:        total,
+		ByCapability: capCounts,
+		ByWave:       waveCounts,
+		ByPurpose:    purposeCounts,
+		ByBottleneck: bottleneckCounts,
+		ByDomain:     domainCounts,
+		Minutes:      minutes,
+	}
 
-	idx, ok := indices[name]
+	t := newTabular("group", "value", "count", "percent", "minutes", "minutesPercent")
+	pct := func(n int) string {
+		return fmt.Sprintf("%.1f", float64(n)/float64(total)*100)
+	}
+	minPct := func(m float64) string {
+		return fmt.Sprintf("%.1f", m/minutes.Total*100)
+	}
+	for _, c := range ds.CapabilityLevels() {
+		m := minutes.ByCapability[c]
+		t.add("aiCapability", c, capCounts[c], pct(capCounts[c]), m, minPct(m))
+	}
+	for wave := 1; wave <= 4; wave++ {
+		m := minutes.ByWave[wave]
+		t.add("agiWave", wave, waveCounts[wave], pct(waveCounts[wave]), m, minPct(m))
+	}
+	for purpose := 1; purpose <= 5; purpose++ {
+		m := minutes.ByPurpose[purpose]
+		t.add("purpose", purpose, purposeCounts[purpose], pct(purposeCounts[purpose]), m, minPct(m))
+	}
+	var bottlenecks []string
+	for b := range bottleneckCounts {
+		bottlenecks = append(bottlenecks, b)
+	}
+	sort.Strings(bottlenecks)
+	for _, b := range bottlenecks {
+		m := minutes.ByBottleneck[b]
+		t.add("bottleneck", b, bottleneckCounts[b], pct(bottleneckCounts[b]), m, minPct(m))
+	}
+	for d := 1; d <= 10; d++ {
+		m := minutes.ByDomain[d]
+		t.add("domain", d, domainCounts[d], pct(domainCounts[d]), m, minPct(m))
+	}
+	emit(report, t)
+}
+
+// cmdIndex shows details about any index loaded from indices/
+func cmdIndex(ds *haai.Dataset, name string) {
+	idx, ok := ds.Index(name)
 	if !ok {
-		fmt.Fprintf(os.Stderr, "Index '%s' not found. Available: abstraction, error-tolerance, purpose\n", name)
+		fmt.Fprintf(os.Stderr, "Index '%s' not found. Available: %s\n", name, strings.Join(ds.IndexIDs(), ", "))
 		os.Exit(1)
 	}
+	if machineOutput() {
+		t := newTabular("level", "name", "definition", "activities")
+		counts := make(map[int]int)
+		for _, val := range idx.Values {
+			counts[val]++
+		}
+		for _, l := range idx.Scale.Levels {
+			t.add(l.Level, l.Name, l.Definition, counts[l.Level])
+		}
+		emit(idx, t)
+		return
+	}
 
 	fmt.Printf("Index: %s\n", idx.IndexName)
 	fmt.Println(strings.Repeat("=", 60))
@@ -964,15 +940,22 @@ func cmdIndex(name string) {
 }
 
 // cmdPurpose lists activities by purpose level (1-5)
-func cmdPurpose(level int) {
-	activities, err := loadActivities()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+func cmdPurpose(ds *haai.Dataset, level int) {
+	activities := ds.Activities()
+	if machineOutput() {
+		var selected []haai.Activity
+		for _, a := range activities {
+			if a.Scores.Purpose == level {
+				selected = append(selected, a)
+			}
+		}
+		emitActivities(ds, selected)
+		return
 	}
 
 	purposeName := getPurposeName(level)
 	fmt.Printf("Activities with Purpose Level %d (%s)\n", level, purposeName)
+	printAssessmentSource(ds)
 	fmt.Println(strings.Repeat("-", 80))
 	fmt.Printf("%-8s %-40s %-12s %-5s\n", "ID", "Name", "Capability", "Wave")
 	fmt.Println(strings.Repeat("-", 80))
@@ -991,41 +974,99 @@ func cmdPurpose(level int) {
 	fmt.Printf("\nTotal: %d activities\n", count)
 }
 
+// cmdLint cross-checks the data files and exits non-zero if anything is inconsistent
+func cmdLint(dir string) {
+	issues, err := haai.Lint(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if machineOutput() {
+		t := newTabular("file", "id", "message")
+		for _, issue := range issues {
+			t.add(issue.File, issue.ID, issue.Message)
+		}
+		if issues == nil {
+			issues = []haai.LintIssue{}
+		}
+		emit(issues, t)
+		if len(issues) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(issues) == 0 {
+		fmt.Println("Lint: no issues found")
+		return
+	}
+
+	fmt.Printf("Lint: %d issues found\n", len(issues))
+	fmt.Println(strings.Repeat("-", 80))
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	os.Exit(1)
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
 		os.Exit(0)
 	}
 
-	cmd := os.Args[1]
-	args := os.Args[2:]
+	rest, opts, err := globalOptions(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(rest) == 0 {
+		printUsage()
+		os.Exit(0)
+	}
+	cmd := rest[0]
+	args := rest[1:]
+
+	dir := findDataDir()
+
+	switch cmd {
+	case "help", "-h", "--help":
+		printUsage()
+		return
+	case "lint":
+		cmdLint(dir)
+		return
+	}
+
+	ds, err := haai.Load(dir, opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
 	switch cmd {
 	case "domains":
-		cmdDomains()
+		cmdDomains(ds)
 	case "domain":
-		if len(args) < 1 {
-			fmt.Fprintln(os.Stderr, "Usage: haai domain <id>")
-			os.Exit(1)
-		}
-		id, err := strconv.Atoi(args[0])
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Invalid domain ID: %s\n", args[0])
-			os.Exit(1)
-		}
-		cmdDomain(id)
+		cmdDomain(ds, args)
 	case "activities":
 		domainFilter := 0
 		if len(args) > 0 {
 			domainFilter, _ = strconv.Atoi(args[0])
 		}
-		cmdActivities(domainFilter)
+		cmdActivities(ds, domainFilter)
 	case "activity":
 		if len(args) < 1 {
 			fmt.Fprintln(os.Stderr, "Usage: haai activity <id>")
 			os.Exit(1)
 		}
-		cmdActivity(args[0])
+		cmdActivity(ds, args[0])
+	case "history":
+		if len(args) < 1 {
+			fmt.Fprintln(os.Stderr, "Usage: haai history <activity-id>")
+			os.Exit(1)
+		}
+		cmdHistory(ds, args[0])
 	case "wave":
 		if len(args) < 1 {
 			fmt.Fprintln(os.Stderr, "Usage: haai wave <1-4>")
@@ -1036,26 +1077,26 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Invalid wave: %s (must be 1-4)\n", args[0])
 			os.Exit(1)
 		}
-		cmdWave(wave)
+		cmdWave(ds, wave)
 	case "capability":
 		if len(args) < 1 {
 			fmt.Fprintln(os.Stderr, "Usage: haai capability <solved|near_solved|partial|early|not_attempted>")
 			os.Exit(1)
 		}
-		cmdCapability(args[0])
+		cmdCapability(ds, args[0])
 	case "bottleneck":
 		if len(args) < 1 {
 			fmt.Fprintln(os.Stderr, "Usage: haai bottleneck <type>")
 			os.Exit(1)
 		}
-		cmdBottleneck(args[0])
+		cmdBottleneck(ds, args[0])
 	case "index":
 		if len(args) < 1 {
 			fmt.Fprintln(os.Stderr, "Usage: haai index <name>")
-			fmt.Fprintln(os.Stderr, "Available indices: abstraction, error-tolerance, purpose")
+			fmt.Fprintf(os.Stderr, "Available indices: %s\n", strings.Join(ds.IndexIDs(), ", "))
 			os.Exit(1)
 		}
-		cmdIndex(args[0])
+		cmdIndex(ds, args[0])
 	case "purpose":
 		if len(args) < 1 {
 			fmt.Fprintln(os.Stderr, "Usage: haai purpose <1-5>")
@@ -1066,23 +1107,51 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Invalid purpose level: %s (must be 1-5)\n", args[0])
 			os.Exit(1)
 		}
-		cmdPurpose(level)
+		cmdPurpose(ds, level)
 	case "search":
-		if len(args) < 1 {
-			fmt.Fprintln(os.Stderr, "Usage: haai search <term>")
-			os.Exit(1)
-		}
-		cmdSearch(strings.Join(args, " "))
+		cmdSearch(ds, args)
 	case "time":
-		cmdTime()
+		cmdTime(ds)
 	case "econ":
-		cmdEcon()
+		cmdEcon(ds, args)
 	case "stats":
-		cmdStats()
+		cmdStats(ds, args)
 	case "table":
-		cmdTable()
-	case "help", "-h", "--help":
-		printUsage()
+		cmdTable(ds)
+	case "query":
+		cmdQuery(ds, args)
+	case "classify":
+		cmdClassify(ds, args)
+	case "validate":
+		cmdValidate(ds, args)
+	case "irr":
+		cmdIRR(ds, args)
+	case "calibrate":
+		cmdCalibrate(ds, args)
+	case "onet":
+		cmdONET(ds, args)
+	case "occupation":
+		cmdOccupation(ds, args)
+	case "occupations":
+		cmdOccupations(ds, args)
+	case "benchmark":
+		cmdBenchmark(ds, args)
+	case "xref":
+		cmdXref(ds, args)
+	case "day":
+		cmdDay(ds, args)
+	case "simulate":
+		cmdSimulate(ds, args)
+	case "forecast":
+		cmdForecast(ds, args)
+	case "rank":
+		cmdRank(ds, args)
+	case "essential":
+		cmdEssential(ds, args)
+	case "diff":
+		cmdDiff(ds, args)
+	case "assessment":
+		cmdAssessment(ds, args)
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", cmd)
 		printUsage()
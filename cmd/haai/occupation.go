@@ -0,0 +1,179 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdOccupation shows the exposure profile of one occupation group
+func cmdOccupation(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("occupation", flag.ExitOnError)
+	weightsFlag := fs.String("weights", "", "task weights: inline (3.5=0.5,4.3=0.5) or a JSON file keyed by ISCO group")
+	rest := parseFlags(fs, args)
+	if len(rest) != 2 || rest[0] != "isco" {
+		fmt.Fprintln(os.Stderr, "Usage: haai occupation isco <group> [--weights 3.5=0.5,4.3=0.5|weights.json]")
+		os.Exit(1)
+	}
+	group, err := strconv.Atoi(rest[1])
+	var groups []int
+	known := false
+	for _, e := range ds.Mappings().ISCOMapping.Mappings {
+		groups = append(groups, e.ISCOGroup)
+		known = known || e.ISCOGroup == group
+	}
+	if err != nil || !known {
+		fmt.Fprintf(os.Stderr, "Error: invalid ISCO group %q (use one of %s)\n", rest[1], joinInts(groups))
+		os.Exit(1)
+	}
+
+	var weights map[haai.HAAIRef]float64
+	if *weightsFlag != "" {
+		if strings.Contains(*weightsFlag, "=") {
+			weights, err = haai.ParseRefWeights(*weightsFlag)
+		} else {
+			var all haai.ISCOWeights
+			all, err = haai.ReadISCOWeights(*weightsFlag)
+			weights = all[group]
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	p, err := ds.OccupationProfile(group, weights)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if machineOutput() {
+		t := newTabular("ref", "name", "weight", "activities", "automationReadiness", "humanEssentiality", "meanWave")
+		for _, c := range p.Components {
+			t.add(string(c.Ref), c.Name, c.Weight, c.Activities, c.Readiness, c.Essentiality, optional(c.MeanWave))
+		}
+		emit(p, t)
+		return
+	}
+
+	fmt.Printf("ISCO-08 Major Group %d: %s\n", p.Group, p.Name)
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("Sample tasks:         %s\n", strings.Join(p.SampleTasks, ", "))
+	fmt.Printf("Activities:           %d\n", p.Activities)
+	readiness := fmt.Sprintf("%.2f", p.Readiness)
+	if band, ok := ds.ReadinessBand(p.Readiness); ok {
+		readiness += " (" + band.Label + ")"
+	}
+	fmt.Printf("Automation readiness: %s\n", readiness)
+	fmt.Printf("Human essentiality:   %.2f\n", p.Essentiality)
+	fmt.Printf("Mean AGI wave:        %s\n", formatMeanWave(p.MeanWave))
+
+	fmt.Println("\nAI Capability:")
+	for _, level := range ds.CapabilityLevels() {
+		share := p.Capability[level]
+		fmt.Printf("  %-14s %4.0f%% %s\n", level, share*100, strings.Repeat("#", int(share*40+0.5)))
+	}
+
+	fmt.Println("\nAGI Wave:")
+	var waves []int
+	for w := range p.Waves {
+		waves = append(waves, w)
+	}
+	sort.Ints(waves)
+	for _, w := range waves {
+		share := p.Waves[w]
+		fmt.Printf("  Wave %-9d %4.0f%% %s\n", w, share*100, strings.Repeat("#", int(share*40+0.5)))
+	}
+
+	fmt.Println("\nComponents:")
+	fmt.Printf("  %-6s %-34s %6s %5s %9s %9s %5s\n", "ID", "Name", "Weight", "Acts", "Readiness", "Essential", "Wave")
+	for _, c := range p.Components {
+		name := c.Name
+		if len(name) > 34 {
+			name = name[:31] + "..."
+		}
+		fmt.Printf("  %-6s %-34s %5.0f%% %5d %9.2f %9.2f %5s\n", c.Ref, name, c.Weight*100, c.Activities,
+			c.Readiness, c.Essentiality, formatMeanWave(c.MeanWave))
+	}
+}
+
+// cmdOccupations ranks the ISCO major groups by automation exposure
+func cmdOccupations(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("occupations", flag.ExitOnError)
+	weightsFile := fs.String("weights", "", "JSON file of task weights keyed by ISCO group")
+	fs.Parse(args)
+
+	var weights haai.ISCOWeights
+	if *weightsFile != "" {
+		var err error
+		if weights, err = haai.ReadISCOWeights(*weightsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	profiles, err := ds.OccupationProfiles(weights)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	levels := ds.CapabilityLevels()
+	if machineOutput() {
+		cols := []string{"rank", "iscoGroup", "iscoName", "activities", "automationReadiness", "humanEssentiality", "meanWave"}
+		cols = append(cols, levels...)
+		t := newTabular(cols...)
+		for i, p := range profiles {
+			row := []any{i + 1, p.Group, p.Name, p.Activities, p.Readiness, p.Essentiality, optional(p.MeanWave)}
+			for _, level := range levels {
+				row = append(row, p.Capability[level])
+			}
+			t.add(row...)
+		}
+		emit(profiles, t)
+		return
+	}
+
+	fmt.Println("ISCO-08 Occupation Exposure (most exposed first)")
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-4s %-3s %-36s %5s %9s %9s %5s  %s\n", "Rank", "Grp", "Occupation Group", "Acts", "Readiness", "Essential", "Wave", "Capability (% of activities)")
+	fmt.Println(strings.Repeat("-", 100))
+	for i, p := range profiles {
+		name := p.Name
+		if len(name) > 36 {
+			name = name[:33] + "..."
+		}
+		var caps []string
+		for _, level := range levels {
+			if share := p.Capability[level]; share > 0 {
+				caps = append(caps, fmt.Sprintf("%s %.0f", abbreviateLevel(level), share*100))
+			}
+		}
+		fmt.Printf("%-4d %-3d %-36s %5d %9.2f %9.2f %5s  %s\n", i+1, p.Group, name, p.Activities,
+			p.Readiness, p.Essentiality, formatMeanWave(p.MeanWave), strings.Join(caps, " "))
+	}
+	var legend []string
+	for _, level := range levels {
+		legend = append(legend, abbreviateLevel(level)+" = "+level)
+	}
+	fmt.Printf("\n%s\n", strings.Join(legend, ", "))
+	if len(weights) > 0 {
+		fmt.Printf("Task weights from %s\n", *weightsFile)
+	}
+}
+
+// abbreviateLevel shortens a capability level for tables ("near_solved" -> "NS")
+func abbreviateLevel(level string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(level, "_") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]))
+		}
+	}
+	return b.String()
+}
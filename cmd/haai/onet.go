@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdONET dispatches the O*NET crosswalk subcommands
+func cmdONET(ds *haai.Dataset, args []string) {
+	if len(args) == 0 || args[0] == "activities" {
+		cmdONETActivities(ds)
+		return
+	}
+	switch args[0] {
+	case "import":
+		cmdONETImport(ds, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown onet command: %s (use activities or import)\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// cmdONETActivities lists HAAI exposure per O*NET Generalized Work Activity
+func cmdONETActivities(ds *haai.Dataset) {
+	xwalk, err := ds.ONETCrosswalk()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if machineOutput() {
+		t := newTabular("onetCategory", "onetActivity", "haaiRefs", "activities", "automationReadiness", "humanEssentiality", "meanWave")
+		for _, x := range xwalk {
+			t.add(x.Category, x.Activity, joinRefs(x.Refs), x.Activities, x.Readiness, x.Essentiality, optional(x.MeanWave))
+		}
+		emit(xwalk, t)
+		return
+	}
+
+	fmt.Println("O*NET Work Activities by HAAI Automation Exposure")
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-44s %-16s %5s %9s %9s %5s\n", "Work Activity", "HAAI", "Acts", "Readiness", "Essential", "Wave")
+	category := ""
+	for _, x := range xwalk {
+		if x.Category != category {
+			category = x.Category
+			fmt.Println(strings.Repeat("-", 100))
+			fmt.Println(category)
+		}
+		name := x.Activity
+		if len(name) > 42 {
+			name = name[:39] + "..."
+		}
+		fmt.Printf("  %-42s %-16s %5d %9.2f %9.2f %5s\n", name, joinRefs(x.Refs), x.Activities,
+			x.Readiness, x.Essentiality, formatMeanWave(x.MeanWave))
+	}
+}
+
+// cmdONETImport scores occupations from a local O*NET database file
+func cmdONETImport(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("onet import", flag.ExitOnError)
+	top := fs.Int("top", 25, "number of occupations to list (0 for all)")
+	files := parseFlags(fs, args)
+	if len(files) != 1 {
+		fmt.Fprintln(os.Stderr, `Usage: haai onet import <"Work Activities.txt"|"Task Statements.txt"> [--top n]`)
+		os.Exit(1)
+	}
+
+	imp, err := ds.ImportONET(files[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	total := len(imp.Occupations)
+	if *top > 0 && len(imp.Occupations) > *top {
+		imp.Occupations = imp.Occupations[:*top]
+	}
+
+	if machineOutput() {
+		t := newTabular("code", "title", "automationReadiness", "humanEssentiality", "meanWave", "coverage", "items")
+		for _, o := range imp.Occupations {
+			t.add(o.Code, o.Title, o.Readiness, o.Essentiality, optional(o.MeanWave), o.Coverage, o.Items)
+		}
+		emit(imp, t)
+		return
+	}
+
+	basis := "work activities weighted by importance"
+	items := "GWAs"
+	if imp.Kind == haai.ONETTasks {
+		basis = "tasks classified into HAAI categories"
+		items = "Tasks"
+	}
+	fmt.Printf("Occupation Automation Exposure from %s (%s)\n", imp.File, basis)
+	fmt.Printf("Showing %d of %d occupations, most exposed first\n", len(imp.Occupations), total)
+	fmt.Println(strings.Repeat("-", 100))
+	fmt.Printf("%-11s %-40s %9s %9s %5s %8s %5s\n", "Code", "Title", "Readiness", "Essential", "Wave", "Coverage", items)
+	fmt.Println(strings.Repeat("-", 100))
+	for _, o := range imp.Occupations {
+		title := o.Title
+		if len(title) > 40 {
+			title = title[:37] + "..."
+		}
+		fmt.Printf("%-11s %-40s %9.2f %9.2f %5s %7.0f%% %5d\n", o.Code, title, o.Readiness, o.Essentiality,
+			formatMeanWave(o.MeanWave), o.Coverage*100, o.Items)
+	}
+	if len(imp.Unmatched) > 0 {
+		fmt.Printf("\nNot in onetMapping (counted against coverage): %s\n", strings.Join(imp.Unmatched, "; "))
+	}
+}
+
+func joinRefs(refs []haai.HAAIRef) string {
+	s := make([]string, len(refs))
+	for i, r := range refs {
+		s[i] = string(r)
+	}
+	return strings.Join(s, ",")
+}
+
+func formatMeanWave(x *float64) string {
+	if x == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", *x)
+}
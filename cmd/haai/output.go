@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// outputFormat is set by the global --format option. "table" is the
+// human-readable default; every other format is rendered by emit.
+var outputFormat = "table"
+
+var outputFormats = []string{"table", "json", "csv", "tsv", "yaml", "markdown"}
+
+// tabular is the flat view of a command's result used by the csv, tsv and
+// markdown formats. Cells are never truncated.
+type tabular struct {
+	headers []string
+	rows    [][]string
+}
+
+func newTabular(headers ...string) *tabular {
+	return &tabular{headers: headers}
+}
+
+func (t *tabular) add(cells ...any) {
+	row := make([]string, len(cells))
+	for i, c := range cells {
+		row[i] = cell(c)
+	}
+	t.rows = append(t.rows, row)
+}
+
+func cell(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		// drop binary rounding noise such as 2.3000000000000003
+		return strconv.FormatFloat(math.Round(x*1e6)/1e6, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// machineOutput reports whether a format other than the default table was requested
+func machineOutput() bool {
+	return outputFormat != "table"
+}
+
+// emit writes a command's result in the selected format: data for json and
+// yaml, tab for csv, tsv and markdown
+func emit(data any, tab *tabular) {
+	var err error
+	switch outputFormat {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(data)
+	case "yaml":
+		var out []byte
+		if out, err = haai.MarshalYAML(data); err == nil {
+			_, err = os.Stdout.Write(out)
+		}
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write(tab.headers)
+		w.WriteAll(tab.rows)
+		err = w.Error()
+	case "tsv":
+		writeTSV(tab)
+	case "markdown":
+		writeMarkdown(tab)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func writeTSV(t *tabular) {
+	clean := strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+	for _, row := range append([][]string{t.headers}, t.rows...) {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = clean.Replace(c)
+		}
+		fmt.Println(strings.Join(cells, "\t"))
+	}
+}
+
+func writeMarkdown(t *tabular) {
+	clean := strings.NewReplacer("|", `\|`, "\n", " ", "\r", " ")
+	line := func(row []string) {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = clean.Replace(c)
+		}
+		fmt.Printf("| %s |\n", strings.Join(cells, " | "))
+	}
+	line(t.headers)
+	sep := make([]string, len(t.headers))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	line(sep)
+	for _, row := range t.rows {
+		line(row)
+	}
+}
+
+// activityTable flattens activities to one row each, with a column per index
+func activityTable(ds *haai.Dataset, activities []haai.Activity) *tabular {
+	headers := []string{"id", "name", "domainId", "categoryId"}
+	headers = append(headers, ds.IndexIDs()...)
+	headers = append(headers, "aiCapability", "bottleneck", "agiWave", "automationReadiness", "humanEssentiality")
+	t := newTabular(headers...)
+	for i := range activities {
+		row := make([]any, len(headers))
+		for j, h := range headers {
+			row[j], _ = activities[i].Field(h)
+		}
+		t.add(row...)
+	}
+	return t
+}
+
+// emitActivities writes activities as merged Activity records (json, yaml) or rows
+func emitActivities(ds *haai.Dataset, activities []haai.Activity) {
+	if activities == nil {
+		activities = []haai.Activity{}
+	}
+	emit(activities, activityTable(ds, activities))
+}
+
+// fieldRecord is an activity reduced to selected fields; it encodes as a
+// JSON object with the fields in selection order
+type fieldRecord struct {
+	keys   []string
+	values []any
+}
+
+func newFieldRecord(a *haai.Activity, fields []string) fieldRecord {
+	r := fieldRecord{keys: fields, values: make([]any, len(fields))}
+	for i, f := range fields {
+		r.values[i], _ = a.Field(f)
+	}
+	return r
+}
+
+func (r fieldRecord) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		val, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
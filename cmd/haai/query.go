@@ -0,0 +1,163 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdQuery filters activities with a query expression, e.g.
+// haai query "domain in (4,5) and abstraction<=2 and capability!=solved" --sort "errorTolerance desc"
+func cmdQuery(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	sortSpec := fs.String("sort", "", `sort keys, e.g. "errorTolerance desc, id"`)
+	fieldList := fs.String("fields", "", "comma-separated fields to show (default: id, name and the fields in the query)")
+	limit := fs.Int("limit", 0, "show at most this many activities")
+	positional := parseFlags(fs, joinSortDirection(args))
+	where := strings.Join(positional, " ")
+
+	sortKeys, err := haai.ParseSort(*sortSpec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	all, err := ds.Query(haai.Query{Where: where, Sort: sortKeys})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	results := all
+	if *limit > 0 && len(results) > *limit {
+		results = results[:*limit]
+	}
+
+	var fields []string
+	for _, f := range strings.Split(*fieldList, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			if !ds.HasField(f) {
+				fmt.Fprintf(os.Stderr, "Error: unknown field %q\n", f)
+				os.Exit(1)
+			}
+			fields = append(fields, f)
+		}
+	}
+
+	if machineOutput() {
+		if len(fields) == 0 {
+			emitActivities(ds, results)
+			return
+		}
+		records := make([]fieldRecord, len(results))
+		t := newTabular(fields...)
+		for i := range results {
+			records[i] = newFieldRecord(&results[i], fields)
+			t.add(records[i].values...)
+		}
+		emit(records, t)
+		return
+	}
+
+	if len(fields) == 0 {
+		fields = defaultQueryFields(ds, where, sortKeys)
+	}
+	widths := make([]int, len(fields))
+	rows := make([][]string, len(results))
+	for j, f := range fields {
+		widths[j] = len(f)
+	}
+	for i := range results {
+		rows[i] = make([]string, len(fields))
+		for j, f := range fields {
+			v, _ := results[i].Field(f)
+			c := cell(v)
+			if len(c) > 40 {
+				c = c[:37] + "..."
+			}
+			rows[i][j] = c
+			if len(c) > widths[j] {
+				widths[j] = len(c)
+			}
+		}
+	}
+	line := func(cells []string) {
+		parts := make([]string, len(cells))
+		for j, c := range cells {
+			parts[j] = fmt.Sprintf("%-*s", widths[j], c)
+		}
+		fmt.Println(strings.TrimRight(strings.Join(parts, " "), " "))
+	}
+	total := len(fields) - 1
+	for _, w := range widths {
+		total += w
+	}
+
+	if where != "" {
+		fmt.Printf("Query: %s\n", where)
+	} else {
+		fmt.Println("Query: all activities")
+	}
+	printAssessmentSource(ds)
+	fmt.Println(strings.Repeat("-", total))
+	line(fields)
+	fmt.Println(strings.Repeat("-", total))
+	for _, row := range rows {
+		line(row)
+	}
+	fmt.Printf("\nShowing %d of %d matching activities\n", len(results), len(all))
+}
+
+// joinSortDirection lets the direction follow --sort as its own argument
+// ("--sort errorTolerance desc") by folding it into the flag value
+func joinSortDirection(args []string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		out = append(out, args[i])
+		if (args[i] == "--sort" || args[i] == "-sort") && i+2 < len(args) {
+			if d := strings.ToLower(args[i+2]); d == "asc" || d == "desc" {
+				out = append(out, args[i+1]+" "+args[i+2])
+				i += 2
+			}
+		}
+	}
+	return out
+}
+
+// defaultQueryFields shows the ID and name followed by every field the
+// query filters or sorts on
+func defaultQueryFields(ds *haai.Dataset, where string, sortKeys []haai.SortKey) []string {
+	fields := []string{"id", "name"}
+	seen := map[string]bool{"id": true, "name": true}
+	add := func(f string) {
+		if !seen[f] {
+			seen[f] = true
+			fields = append(fields, f)
+		}
+	}
+	var walk func(c *haai.Condition)
+	walk = func(c *haai.Condition) {
+		if c.Field != "" {
+			add(c.Field)
+		}
+		for i := range c.All {
+			walk(&c.All[i])
+		}
+		for i := range c.Any {
+			walk(&c.Any[i])
+		}
+	}
+	if strings.TrimSpace(where) != "" {
+		if c, err := ds.ParseQuery(where); err == nil {
+			walk(c)
+		}
+	}
+	for _, k := range sortKeys {
+		add(k.Field)
+	}
+	if len(fields) == 2 {
+		fields = append(fields, "aiCapability", "agiWave")
+	}
+	return fields
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdRank lists activities ordered by automationReadiness and compares the
+// wave implied by the score's interpretation band with the assessed agiWave
+func cmdRank(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("rank", flag.ExitOnError)
+	domain := fs.Int("domain", 0, "only rank activities in this domain")
+	category := fs.String("category", "", "only rank activities in this category (e.g. 3.3)")
+	limit := fs.Int("limit", 0, "show at most this many activities")
+	asc := fs.Bool("asc", false, "least ready first")
+	fs.Parse(args)
+
+	if ds.CompositeWeights() == nil {
+		fmt.Fprintln(os.Stderr, "Error: the active assessment has no compositeWeights")
+		os.Exit(1)
+	}
+
+	var ranked []haai.Activity
+	for _, a := range ds.Activities() {
+		if *domain > 0 && haai.DomainFromID(a.ID) != *domain {
+			continue
+		}
+		if *category != "" && a.CategoryID != *category {
+			continue
+		}
+		ranked = append(ranked, a)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if *asc {
+			return ranked[i].Scores.AutomationReadiness < ranked[j].Scores.AutomationReadiness
+		}
+		return ranked[i].Scores.AutomationReadiness > ranked[j].Scores.AutomationReadiness
+	})
+	if *limit > 0 && len(ranked) > *limit {
+		ranked = ranked[:*limit]
+	}
+	rows := make([]rankRow, len(ranked))
+	var summary rankSummary
+	for i, a := range ranked {
+		r := rankRow{
+			Rank:                i + 1,
+			ID:                  a.ID,
+			Name:                a.Name,
+			AutomationReadiness: a.Scores.AutomationReadiness,
+			AssessedWave:        a.Scores.AGIWave,
+		}
+		if band, ok := ds.ReadinessBand(a.Scores.AutomationReadiness); ok && band.Wave > 0 {
+			implied, delta := band.Wave, a.Scores.AGIWave-band.Wave
+			r.ImpliedWave, r.Delta = &implied, &delta
+			switch {
+			case delta == 0:
+				summary.Agree++
+			case delta > 0:
+				summary.Later++
+			default:
+				summary.Earlier++
+			}
+		}
+		rows[i] = r
+	}
+
+	if machineOutput() {
+		t := newTabular("rank", "id", "name", "automationReadiness", "impliedWave", "assessedWave", "delta")
+		for _, r := range rows {
+			t.add(r.Rank, r.ID, r.Name, r.AutomationReadiness, optionalInt(r.ImpliedWave), r.AssessedWave, optionalInt(r.Delta))
+		}
+		emit(rankReport{Assessment: ds.AssessmentFile(), Activities: rows, Summary: summary}, t)
+		return
+	}
+
+	fmt.Println("Activities by Automation Readiness")
+	printAssessmentSource(ds)
+	fmt.Println(strings.Repeat("-", 86))
+	fmt.Printf("%-4s %-8s %-36s %6s %7s %8s %6s\n", "#", "ID", "Name", "Score", "Implied", "Assessed", "Delta")
+	fmt.Println(strings.Repeat("-", 86))
+
+	for _, r := range rows {
+		name := r.Name
+		if len(name) > 36 {
+			name = name[:33] + "..."
+		}
+		implied := "-"
+		delta := "-"
+		if r.ImpliedWave != nil {
+			implied = fmt.Sprintf("%d", *r.ImpliedWave)
+			switch d := *r.Delta; {
+			case d == 0:
+				delta = "="
+			case d > 0:
+				delta = fmt.Sprintf("+%d", d)
+			default:
+				delta = fmt.Sprintf("%d", d)
+			}
+		}
+		fmt.Printf("%-4d %-8s %-36s %6.2f %7s %8d %6s\n",
+			r.Rank, r.ID, name, r.AutomationReadiness, implied, r.AssessedWave, delta)
+	}
+
+	fmt.Printf("\nTotal: %d activities\n", len(rows))
+	fmt.Printf("Assessed wave matches implied band: %d, later (+): %d, earlier (-): %d\n", summary.Agree, summary.Later, summary.Earlier)
+}
+
+// rankRow is one ranked activity. ImpliedWave and Delta (assessed minus
+// implied) are nil when no readiness band carries a wave.
+type rankRow struct {
+	Rank                int     `json:"rank"`
+	ID                  string  `json:"id"`
+	Name                string  `json:"name"`
+	AutomationReadiness float64 `json:"automationReadiness"`
+	ImpliedWave         *int    `json:"impliedWave"`
+	AssessedWave        int     `json:"assessedWave"`
+	Delta               *int    `json:"delta"`
+}
+
+// rankSummary counts how the assessed waves compare with the implied ones
+type rankSummary struct {
+	Agree   int `json:"agree"`
+	Later   int `json:"later"`
+	Earlier int `json:"earlier"`
+}
+
+// rankReport is the machine-readable form of cmdRank
+type rankReport struct {
+	Assessment string      `json:"assessment"`
+	Activities []rankRow   `json:"activities"`
+	Summary    rankSummary `json:"summary"`
+}
+
+func optionalInt(x *int) any {
+	if x == nil {
+		return ""
+	}
+	return *x
+}
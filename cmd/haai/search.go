@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdSearch ranks activities against free text and shows where each matched
+func cmdSearch(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "show at most this many activities (0 for all)")
+	terms := strings.Join(parseFlags(fs, args), " ")
+	if strings.TrimSpace(terms) == "" {
+		fmt.Fprintln(os.Stderr, "Usage: haai search <terms> [--limit n]")
+		os.Exit(1)
+	}
+
+	hits := ds.Search(terms, 0)
+	total := len(hits)
+	if *limit > 0 && len(hits) > *limit {
+		hits = hits[:*limit]
+	}
+
+	if machineOutput() {
+		t := newTabular("id", "name", "score", "field", "text")
+		for _, h := range hits {
+			m := h.Matches[0]
+			t.add(h.Activity.ID, h.Activity.Name, fmt.Sprintf("%.3f", h.Score), m.Field, m.Text)
+		}
+		if hits == nil {
+			hits = []haai.SearchHit{}
+		}
+		emit(hits, t)
+		return
+	}
+
+	open, close := "[", "]"
+	if isTerminal(os.Stdout) {
+		open, close = "\x1b[1;33m", "\x1b[0m"
+	}
+
+	fmt.Printf("Search results for: %s\n", terms)
+	printAssessmentSource(ds)
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%-8s %-40s %6s %-12s %-5s\n", "ID", "Name", "Score", "Capability", "Wave")
+	fmt.Println(strings.Repeat("-", 80))
+
+	for _, h := range hits {
+		a := h.Activity
+		name := a.Name
+		if len(name) > 40 {
+			name = name[:37] + "..."
+		}
+		fmt.Printf("%-8s %-40s %6.2f %-12s %-5d\n", a.ID, name, h.Score, a.Scores.AICapability, a.Scores.AGIWave)
+		for i, m := range h.Matches {
+			if i == 2 {
+				break
+			}
+			fmt.Printf("         %s: %s\n", m.Field, m.Highlight(open, close, 60))
+		}
+	}
+	fmt.Printf("\nShowing %d of %d matching activities\n", len(hits), total)
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdSimulate projects the automated share of activities, time use and
+// value year by year for each adoption scenario, side by side
+func cmdSimulate(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	file := fs.String("scenarios", "", "scenarios file (default: scenarios.json in the data directory)")
+	names := fs.String("scenario", "", "comma-separated scenarios to run (default: all)")
+	from := fs.Int("from", 0, "first year (default: start of the first wave window)")
+	to := fs.Int("to", 0, "last year (default: 15 years after --from)")
+	if rest := parseFlags(fs, args); len(rest) > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: haai simulate [--scenarios file] [--scenario fast,baseline] [--from year] [--to year]")
+		os.Exit(1)
+	}
+
+	var sf *haai.ScenariosFile
+	var err error
+	if *file != "" {
+		sf, err = haai.ReadScenarios(*file)
+	} else {
+		sf, err = ds.Scenarios()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *from == 0 {
+		if af := ds.Assessment(); af != nil {
+			for _, w := range af.WaveWindows() {
+				if *from == 0 || w.Start < *from {
+					*from = w.Start
+				}
+			}
+		}
+	}
+	if *to == 0 {
+		*to = *from + 15
+	}
+	var selected []string
+	if *names != "" {
+		for _, n := range strings.Split(*names, ",") {
+			selected = append(selected, strings.TrimSpace(n))
+		}
+	}
+	sim, err := ds.Simulate(sf, selected, *from, *to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if machineOutput() {
+		cols := []string{"scenario", "year", "activityShare", "minutes", "valueBillions"}
+		for _, w := range sim.Windows {
+			cols = append(cols, fmt.Sprintf("wave%d", w.Wave))
+		}
+		t := newTabular(cols...)
+		for _, r := range sim.Scenarios {
+			for _, y := range r.Years {
+				row := []any{r.Scenario, y.Year, y.ActivityShare, y.Minutes, y.ValueBillions}
+				for _, w := range sim.Windows {
+					row = append(row, y.Waves[w.Wave])
+				}
+				t.add(row...)
+			}
+		}
+		emit(sim, t)
+		return
+	}
+
+	fmt.Printf("Adoption Scenarios %d-%d\n", sim.From, sim.To)
+	printAssessmentSource(ds)
+	var windows []string
+	for _, w := range sim.Windows {
+		windows = append(windows, fmt.Sprintf("wave %d %s", w.Wave, w))
+	}
+	fmt.Printf("Wave windows: %s\n", strings.Join(windows, ", "))
+	for _, r := range sim.Scenarios {
+		fmt.Printf("  %-10s %s\n", r.Scenario, r.Description)
+	}
+
+	section := func(title string, value func(haai.SimulationYear) string) {
+		fmt.Printf("\n%s\n", title)
+		fmt.Printf("%-6s", "Year")
+		for _, r := range sim.Scenarios {
+			fmt.Printf(" %12s", r.Scenario)
+		}
+		fmt.Println()
+		fmt.Println(strings.Repeat("-", 6+13*len(sim.Scenarios)))
+		for i := range sim.Scenarios[0].Years {
+			fmt.Printf("%-6d", sim.Scenarios[0].Years[i].Year)
+			for _, r := range sim.Scenarios {
+				fmt.Printf(" %12s", value(r.Years[i]))
+			}
+			fmt.Println()
+		}
+	}
+	section(fmt.Sprintf("Automated Share of Activities (%d activities)", sim.Activities), func(y haai.SimulationYear) string {
+		return fmt.Sprintf("%.1f%%", y.ActivityShare*100)
+	})
+	section(fmt.Sprintf("Automated ATUS Minutes per Day (of %.0f)", sim.TotalMinutes), func(y haai.SimulationYear) string {
+		return fmt.Sprintf("%.0f", y.Minutes)
+	})
+	section(fmt.Sprintf("Automated Annual Value, $B (of %.0f)", sim.TotalValueBillions), func(y haai.SimulationYear) string {
+		return fmt.Sprintf("%.0f", y.ValueBillions)
+	})
+}
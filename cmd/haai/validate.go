@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdValidate runs the validation.json suites and records passing ones
+func cmdValidate(ds *haai.Dataset, args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	verbose := fs.Bool("verbose", false, "list passing cases too")
+	dryRun := fs.Bool("dry-run", false, "do not write lastCompleted back to validation.json")
+	date := fs.String("date", time.Now().Format("2006-01-02"), "date recorded as lastCompleted (YYYY-MM-DD)")
+	fs.Parse(args)
+
+	report, err := ds.Validate()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !*dryRun {
+		if err := ds.RecordValidation(report, *date); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if machineOutput() {
+		t := newTabular("suite", "case", "status", "message")
+		for _, s := range report.Suites {
+			for _, c := range s.Checks {
+				t.add(s.Name, c.Case, c.Status, c.Message)
+			}
+		}
+		emit(report, t)
+		if !report.OK() {
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("Validation Report")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, s := range report.Suites {
+		status := "PASS"
+		if !s.OK() {
+			status = "FAIL"
+		}
+		fmt.Printf("%-4s  %-28s %3d passed  %3d failed  %3d warnings\n", status, s.Name, s.Passed, s.Failed, s.Warnings)
+		for _, c := range s.Checks {
+			if c.Status == haai.CheckPass && !*verbose {
+				continue
+			}
+			line := fmt.Sprintf("        %-4s %s", c.Status, c.Case)
+			if c.Message != "" {
+				line += ": " + c.Message
+			}
+			fmt.Println(line)
+		}
+	}
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("Total: %d passed, %d failed, %d warnings\n", report.Passed, report.Failed, report.Warnings)
+	if report.ClassifierCases > 0 {
+		fmt.Printf("Classifier agrees with %d of %d recorded classifications (%.0f%%)\n",
+			report.ClassifierAgreed, report.ClassifierCases, 100*float64(report.ClassifierAgreed)/float64(report.ClassifierCases))
+	}
+	if *dryRun {
+		fmt.Println("Dry run: validation.json not updated")
+	} else {
+		fmt.Printf("Recorded lastCompleted %s for passing suites in validation.json\n", *date)
+	}
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cederikdotcom/haai"
+)
+
+// cmdXref lists the HAAI activities an external code maps to
+func cmdXref(ds *haai.Dataset, args []string) {
+	if len(args) == 1 {
+		cmdXrefCodes(ds, args[0])
+		return
+	}
+	if len(args) < 2 {
+		fmt.Fprintf(os.Stderr, "Usage: haai xref <%s> [code]\n", strings.Join(haai.CrosswalkSystems(), "|"))
+		os.Exit(1)
+	}
+	matches, err := ds.Xref(args[0], strings.Join(args[1:], " "))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if machineOutput() {
+		t := newTabular("system", "code", "name", "haaiRefs", "activityId", "activity", "aiCapability", "agiWave")
+		for _, m := range matches {
+			for _, a := range m.Activities {
+				t.add(m.System, m.Code, m.Name, joinRefs(m.Refs), a.ID, a.Name, a.Scores.AICapability, a.Scores.AGIWave)
+			}
+		}
+		emit(matches, t)
+		return
+	}
+
+	for i, m := range matches {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s %s: %s\n", systemName(ds, m.System), m.Code, m.Name)
+		var refs []string
+		for _, r := range m.Refs {
+			refs = append(refs, fmt.Sprintf("%s %s", r, ds.RefName(r)))
+		}
+		fmt.Printf("Maps to: %s\n", strings.Join(refs, "; "))
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Printf("%-8s %-40s %-12s %-5s\n", "ID", "Name", "Capability", "Wave")
+		for _, a := range m.Activities {
+			name := a.Name
+			if len(name) > 40 {
+				name = name[:37] + "..."
+			}
+			fmt.Printf("%-8s %-40s %-12s %-5d\n", a.ID, name, a.Scores.AICapability, a.Scores.AGIWave)
+		}
+		fmt.Printf("\nTotal: %d activities\n", len(m.Activities))
+	}
+}
+
+// cmdXrefCodes lists the codes of one system
+func cmdXrefCodes(ds *haai.Dataset, system string) {
+	codes := ds.XrefCodes(system)
+	if len(codes) == 0 {
+		fmt.Fprintf(os.Stderr, "Unknown system: %s (use %s)\n", system, strings.Join(haai.CrosswalkSystems(), ", "))
+		os.Exit(1)
+	}
+	if machineOutput() {
+		t := newTabular("system", "code", "name", "haaiRefs")
+		for _, e := range codes {
+			t.add(e.System, e.Code, e.Name, joinRefs(e.Refs))
+		}
+		emit(codes, t)
+		return
+	}
+	fmt.Printf("%s codes in mappings.json\n", systemName(ds, strings.ToLower(system)))
+	fmt.Println(strings.Repeat("-", 80))
+	for _, e := range codes {
+		fmt.Printf("  %-46s %-20s %s\n", e.Code, joinRefs(e.Refs), e.Name)
+	}
+}
+
+// printExternalRefs prints the "External Mappings" section of activity and
+// domain details. O*NET work activities are grouped by their group name.
+func printExternalRefs(ds *haai.Dataset, refs []haai.ExternalRef) {
+	if len(refs) == 0 {
+		return
+	}
+	fmt.Println("External Mappings:")
+	var onetGroups []string
+	onet := make(map[string][]string)
+	onetVia := make(map[string]haai.HAAIRef)
+	for _, x := range refs {
+		if x.System != haai.SystemONET {
+			continue
+		}
+		if _, ok := onet[x.Name]; !ok {
+			onetGroups = append(onetGroups, x.Name)
+			onetVia[x.Name] = x.Via
+		}
+		onet[x.Name] = append(onet[x.Name], x.Code)
+	}
+	for _, g := range onetGroups {
+		fmt.Printf("  %-12s %s (via %s): %s\n", systemName(ds, haai.SystemONET), g, onetVia[g], strings.Join(onet[g], "; "))
+	}
+	for _, x := range refs {
+		if x.System == haai.SystemONET {
+			continue
+		}
+		label := x.Code
+		if x.System == haai.SystemATUS || x.System == haai.SystemISCO {
+			label += " " + x.Name
+		}
+		fmt.Printf("  %-12s %s (via %s)\n", systemName(ds, x.System), label, x.Via)
+	}
+	fmt.Println()
+}
+
+// systemName returns the short display name of an external system
+func systemName(ds *haai.Dataset, system string) string {
+	switch system {
+	case haai.SystemONET:
+		return "O*NET"
+	case haai.SystemATUS:
+		return "ATUS"
+	case haai.SystemISCO:
+		return "ISCO-08"
+	}
+	if src, ok := ds.Mappings().Sources[system]; ok && src.Name != "" {
+		return src.Name
+	}
+	return system
+}
+
+// formatExternalRefs writes external codes as one table cell, e.g.
+// "atus 02; isco 4"
+func formatExternalRefs(refs []haai.ExternalRef) string {
+	parts := make([]string, len(refs))
+	for i, x := range refs {
+		parts[i] = x.System + " " + x.Code
+	}
+	return strings.Join(parts, "; ")
+}
+
+// mergeExternalRefs appends the codes in more that refs does not have yet
+func mergeExternalRefs(refs, more []haai.ExternalRef) []haai.ExternalRef {
+	for _, x := range more {
+		seen := false
+		for _, y := range refs {
+			if x.System == y.System && x.Code == y.Code {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			refs = append(refs, x)
+		}
+	}
+	return refs
+}
+
+// nonNilRefs returns refs, or an empty list so JSON shows [] rather than null
+func nonNilRefs(refs []haai.ExternalRef) []haai.ExternalRef {
+	if refs == nil {
+		return []haai.ExternalRef{}
+	}
+	return refs
+}
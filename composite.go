@@ -0,0 +1,142 @@
+package haai
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReadinessBand is one interpretation band of the automationReadiness score
+// from scoring.json, e.g. "3.0-3.9": "Moderate automation readiness - Wave 2".
+type ReadinessBand struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Label string  `json:"label"`
+	Wave  int     `json:"wave"`
+}
+
+var bandWavePattern = regexp.MustCompile(`Wave\s+(\d+)`)
+
+// ReadinessBands returns the automationReadiness interpretation bands, highest first.
+func (ds *Dataset) ReadinessBands() []ReadinessBand {
+	var bands []ReadinessBand
+	for key, label := range ds.scoring.CompositeScores.AutomationReadiness.Interpretation {
+		lo, hi, ok := strings.Cut(key, "-")
+		if !ok {
+			continue
+		}
+		min, err1 := strconv.ParseFloat(strings.TrimSpace(lo), 64)
+		max, err2 := strconv.ParseFloat(strings.TrimSpace(hi), 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		band := ReadinessBand{Min: min, Max: max, Label: label}
+		if m := bandWavePattern.FindStringSubmatch(label); m != nil {
+			band.Wave, _ = strconv.Atoi(m[1])
+		}
+		bands = append(bands, band)
+	}
+	sort.Slice(bands, func(i, j int) bool { return bands[i].Min > bands[j].Min })
+	return bands
+}
+
+// ReadinessBand returns the interpretation band for an automationReadiness
+// score. Bands are matched by their lower bound so gaps such as 3.9-4.0 fall
+// into the band below.
+func (ds *Dataset) ReadinessBand(score float64) (ReadinessBand, bool) {
+	bands := ds.ReadinessBands()
+	for _, b := range bands {
+		if score >= b.Min {
+			return b, true
+		}
+	}
+	if len(bands) > 0 {
+		return bands[len(bands)-1], true
+	}
+	return ReadinessBand{}, false
+}
+
+// CompositeWeights returns the weights of the active assessment, or nil if it has none.
+func (ds *Dataset) CompositeWeights() *CompositeWeights {
+	if ds.assessment == nil {
+		return nil
+	}
+	return ds.assessment.CompositeWeights
+}
+
+// AutomationReadiness computes the automationReadiness composite defined in
+// scoring.json for the given scores:
+//
+//	((5 - abstraction) * w.abstraction) + ((4 - errorTolerance) * w.errorTolerance) +
+//	((4 - interpersonalComplexity) * w.interpersonalComplexity) + (capabilityScore * w.aiCapability)
+//
+// The capability mapping comes from the weights, falling back to scoring.json,
+// and the result is clamped to the range declared there.
+func (ds *Dataset) AutomationReadiness(s Scores, w *CompositeWeights) float64 {
+	if w == nil {
+		return 0
+	}
+	def := ds.scoring.CompositeScores.AutomationReadiness
+	capScore, ok := w.CapabilityMapping[s.AICapability]
+	if !ok {
+		capScore = def.CapabilityMapping[s.AICapability]
+	}
+	r := w.AutomationReadiness
+	score := float64(5-s.Abstraction)*r.Abstraction +
+		float64(4-s.ErrorTolerance)*r.ErrorTolerance +
+		float64(4-s.InterpersonalComplexity)*r.InterpersonalComplexity +
+		capScore*r.AICapability
+	if def.Range.Max > def.Range.Min {
+		score = math.Max(def.Range.Min, math.Min(def.Range.Max, score))
+	}
+	return score
+}
+
+// Essentiality is the result of evaluating the humanEssentiality rules for one activity.
+type Essentiality struct {
+	Score   float64              `json:"score"`
+	Max     float64              `json:"max"`
+	Factors []EssentialityFactor `json:"factors"`
+}
+
+// EssentialityFactors returns the humanEssentiality rules from scoring.json.
+func (ds *Dataset) EssentialityFactors() []EssentialityFactor {
+	return ds.scoring.CompositeScores.HumanEssentiality.Factors
+}
+
+// HumanEssentiality evaluates the humanEssentiality rules against an activity
+// and returns the weighted score together with the factors that fired.
+func (ds *Dataset) HumanEssentiality(a *Activity) Essentiality {
+	var e Essentiality
+	for _, f := range ds.EssentialityFactors() {
+		e.Max += f.Weight
+		if f.When.Match(a) {
+			e.Score += f.Weight
+			e.Factors = append(e.Factors, f)
+		}
+	}
+	return e
+}
+
+// validateEssentialityFactors rejects rules that name unknown fields or operators
+func (ds *Dataset) validateEssentialityFactors() error {
+	for _, f := range ds.EssentialityFactors() {
+		if err := ds.ValidateCondition(&f.When); err != nil {
+			return fmt.Errorf("scoring.json: humanEssentiality factor %q: %w", f.ID, err)
+		}
+	}
+	return nil
+}
+
+// computeComposites fills the composite score fields of every activity
+func (ds *Dataset) computeComposites() {
+	w := ds.CompositeWeights()
+	for i := range ds.activities {
+		a := &ds.activities[i]
+		a.Scores.AutomationReadiness = ds.AutomationReadiness(a.Scores, w)
+		a.Scores.HumanEssentiality = ds.HumanEssentiality(a).Score
+	}
+}
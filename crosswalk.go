@@ -0,0 +1,373 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HAAIRef is a reference from mappings.json to a HAAI domain ("7"), a range
+// of domains ("1-8") or a category ("7.4"). The file writes domains as JSON
+// numbers or strings and categories as strings; all decode to the ID string.
+type HAAIRef string
+
+func (r *HAAIRef) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*r = HAAIRef(strings.TrimSpace(s))
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("HAAI reference must be a domain number or category ID, got %s", data)
+	}
+	*r = HAAIRef(strconv.Itoa(n))
+	return nil
+}
+
+// IsDomain reports whether the reference names whole domains.
+func (r HAAIRef) IsDomain() bool {
+	return !strings.Contains(string(r), ".")
+}
+
+// Domains returns the first and last domain a domain reference covers.
+func (r HAAIRef) Domains() (first, last int, ok bool) {
+	if !r.IsDomain() {
+		return 0, 0, false
+	}
+	lo, hi, isRange := strings.Cut(string(r), "-")
+	first, err := strconv.Atoi(strings.TrimSpace(lo))
+	if err != nil {
+		return 0, 0, false
+	}
+	if !isRange {
+		return first, first, true
+	}
+	last, err = strconv.Atoi(strings.TrimSpace(hi))
+	if err != nil || last < first {
+		return 0, 0, false
+	}
+	return first, last, true
+}
+
+// ResolveRef returns the activities a reference covers: every activity in
+// the domains or category.
+func (ds *Dataset) ResolveRef(r HAAIRef) ([]Activity, error) {
+	var acts []Activity
+	if r.IsDomain() {
+		first, last, ok := r.Domains()
+		if !ok {
+			return nil, fmt.Errorf("invalid HAAI reference %q", r)
+		}
+		for id := first; id <= last; id++ {
+			if _, ok := ds.Domain(id); !ok {
+				return nil, fmt.Errorf("domain %d does not exist", id)
+			}
+		}
+		for _, a := range ds.activities {
+			if a.DomainID >= first && a.DomainID <= last {
+				acts = append(acts, a)
+			}
+		}
+		return acts, nil
+	}
+	if _, ok := ds.Category(string(r)); !ok {
+		return nil, fmt.Errorf("category %s does not exist", r)
+	}
+	for _, a := range ds.activities {
+		if a.CategoryID == string(r) {
+			acts = append(acts, a)
+		}
+	}
+	return acts, nil
+}
+
+// RefName returns the name of the referenced domain or category, "" if it
+// does not exist.
+func (ds *Dataset) RefName(r HAAIRef) string {
+	if r.IsDomain() {
+		first, last, ok := r.Domains()
+		if !ok {
+			return ""
+		}
+		if first < last {
+			return fmt.Sprintf("Domains %d-%d", first, last)
+		}
+		if d, ok := ds.Domain(first); ok {
+			return d.Name
+		}
+		return ""
+	}
+	if c, ok := ds.Category(string(r)); ok {
+		return c.Name
+	}
+	return ""
+}
+
+// ResolveRefs returns the activities covered by any of the references, each
+// once, in taxonomy order.
+func (ds *Dataset) ResolveRefs(refs []HAAIRef) ([]Activity, error) {
+	seen := make(map[string]bool)
+	var acts []Activity
+	for _, r := range refs {
+		resolved, err := ds.ResolveRef(r)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range resolved {
+			if !seen[a.ID] {
+				seen[a.ID] = true
+				acts = append(acts, a)
+			}
+		}
+	}
+	sort.SliceStable(acts, func(i, j int) bool { return CompareIDs(acts[i].ID, acts[j].ID) < 0 })
+	return acts, nil
+}
+
+// ActivityRollup summarises the scores of a set of activities.
+type ActivityRollup struct {
+	Activities   int            `json:"activities"`
+	Readiness    float64        `json:"automationReadiness"` // mean
+	Essentiality float64        `json:"humanEssentiality"`   // mean
+	MeanWave     *float64       `json:"meanWave"`            // over activities with a wave
+	Capability   map[string]int `json:"capability"`          // activities per aiCapability level
+	Waves        map[int]int    `json:"waves"`               // activities per agiWave
+}
+
+// Rollup summarises the scores of acts.
+func Rollup(acts []Activity) ActivityRollup {
+	r := ActivityRollup{Activities: len(acts), Capability: make(map[string]int), Waves: make(map[int]int)}
+	if len(acts) == 0 {
+		return r
+	}
+	var waves, waveSum float64
+	for _, a := range acts {
+		r.Readiness += a.Scores.AutomationReadiness
+		r.Essentiality += a.Scores.HumanEssentiality
+		if a.Scores.AICapability != "" {
+			r.Capability[a.Scores.AICapability]++
+		}
+		if a.Scores.AGIWave > 0 {
+			r.Waves[a.Scores.AGIWave]++
+			waves++
+			waveSum += float64(a.Scores.AGIWave)
+		}
+	}
+	r.Readiness /= float64(len(acts))
+	r.Essentiality /= float64(len(acts))
+	if waves > 0 {
+		mean := waveSum / waves
+		r.MeanWave = &mean
+	}
+	return r
+}
+
+// External classification systems in mappings.json
+const (
+	SystemONET        = "onet"
+	SystemATUS        = "atus"
+	SystemISCO        = "isco"
+	SystemBehavior1K  = BenchmarkBehavior1K
+	SystemActivityNet = BenchmarkActivityNet
+)
+
+// CrosswalkSystems returns the systems accepted by Xref, in mappings.json order.
+func CrosswalkSystems() []string {
+	return []string{SystemONET, SystemATUS, SystemBehavior1K, SystemActivityNet, SystemISCO}
+}
+
+// CrosswalkEntry is one external code and the HAAI domains and categories
+// it maps to. O*NET entries are Generalized Work Activities, named after
+// their group; benchmark entries use the category name as the code.
+type CrosswalkEntry struct {
+	System string    `json:"system"`
+	Code   string    `json:"code"`
+	Name   string    `json:"name"`
+	Refs   []HAAIRef `json:"haaiRefs"`
+}
+
+// ExternalRef is an external code that reaches an activity, and the
+// reference it reaches it through (the most specific one if several do).
+type ExternalRef struct {
+	CrosswalkEntry
+	Via HAAIRef `json:"via"`
+}
+
+// crosswalkEntries flattens every mapping section of mappings.json
+func (m *Mappings) crosswalkEntries() []CrosswalkEntry {
+	var out []CrosswalkEntry
+	for _, e := range m.ONETMapping.Mappings {
+		for _, a := range e.ONETActivities {
+			out = append(out, CrosswalkEntry{System: SystemONET, Code: a, Name: e.ONETCategory, Refs: e.HAAIDomains})
+		}
+	}
+	for _, e := range m.ATUSMapping.Mappings {
+		refs := make([]HAAIRef, len(e.HAICategories))
+		for i, c := range e.HAICategories {
+			refs[i] = HAAIRef(c)
+		}
+		out = append(out, CrosswalkEntry{System: SystemATUS, Code: e.ATUSCode, Name: e.ATUSCategory, Refs: refs})
+	}
+	for _, e := range m.Behavior1KMapping.Mappings {
+		out = append(out, CrosswalkEntry{System: SystemBehavior1K, Code: e.BehaviorCategory,
+			Name: strings.Join(e.TaskExamples, ", "), Refs: e.HAAICategories})
+	}
+	for _, e := range m.ActivityNetMapping.Mappings {
+		out = append(out, CrosswalkEntry{System: SystemActivityNet, Code: e.ActionType,
+			Name: strings.Join(e.Examples, ", "), Refs: e.HAAIDomains})
+	}
+	for _, e := range m.ISCOMapping.Mappings {
+		out = append(out, CrosswalkEntry{System: SystemISCO, Code: strconv.Itoa(e.ISCOGroup), Name: e.ISCOName, Refs: e.HAAICategories})
+	}
+	return out
+}
+
+// buildReverseCrosswalk indexes, for every activity, the external codes
+// whose domain or category references include it. References to unknown
+// domains or categories are skipped here; Lint reports them.
+func (ds *Dataset) buildReverseCrosswalk() {
+	ds.crosswalk = ds.mappings.crosswalkEntries()
+	ds.reverse = make(map[string][]ExternalRef)
+	for _, e := range ds.crosswalk {
+		via := make(map[string]HAAIRef)
+		var order []string
+		for _, r := range e.Refs {
+			acts, err := ds.ResolveRef(r)
+			if err != nil {
+				continue
+			}
+			for _, a := range acts {
+				prev, seen := via[a.ID]
+				if !seen {
+					order = append(order, a.ID)
+				}
+				if !seen || (prev.IsDomain() && !r.IsDomain()) {
+					via[a.ID] = r
+				}
+			}
+		}
+		for _, id := range order {
+			ds.reverse[id] = append(ds.reverse[id], ExternalRef{CrosswalkEntry: e, Via: via[id]})
+		}
+	}
+}
+
+// ExternalRefs returns the external codes that map to an activity, grouped
+// by system in mappings.json order.
+func (ds *Dataset) ExternalRefs(activityID string) []ExternalRef {
+	return ds.reverse[activityID]
+}
+
+// DomainExternalRefs returns the external codes that map to any activity in
+// a domain, each once, with the reference through which they first reach it.
+func (ds *Dataset) DomainExternalRefs(domainID int) []ExternalRef {
+	var out []ExternalRef
+	seen := make(map[string]bool)
+	for _, a := range ds.activities {
+		if a.DomainID != domainID {
+			continue
+		}
+		for _, x := range ds.reverse[a.ID] {
+			key := x.System + "\x00" + x.Code
+			if !seen[key] {
+				seen[key] = true
+				out = append(out, x)
+			}
+		}
+	}
+	order := make(map[string]int)
+	for i, s := range CrosswalkSystems() {
+		order[s] = i
+	}
+	sort.SliceStable(out, func(i, j int) bool { return order[out[i].System] < order[out[j].System] })
+	return out
+}
+
+// XrefMatch is an external code with the activities it maps to.
+type XrefMatch struct {
+	CrosswalkEntry
+	Activities []Activity `json:"activities"`
+}
+
+// Xref looks up an external code in one system. The code matches
+// case-insensitively; O*NET also matches a whole work activity group by its
+// name (returned as one match), and an ISCO-08 sub-major group code such as
+// "42" matches its major group.
+func (ds *Dataset) Xref(system, code string) ([]XrefMatch, error) {
+	system = strings.ToLower(strings.TrimSpace(system))
+	if !containsString(CrosswalkSystems(), system) {
+		return nil, fmt.Errorf("unknown system %q (use %s)", system, strings.Join(CrosswalkSystems(), ", "))
+	}
+	code = strings.TrimSpace(code)
+	asked := code
+	if system == SystemISCO && len(code) > 1 {
+		major, ok := iscoSubMajorGroup(code)
+		if !ok {
+			return nil, fmt.Errorf("no %s code %q in mappings.json", system, code)
+		}
+		code = major
+	}
+	var out []XrefMatch
+	var group *CrosswalkEntry
+	for _, e := range ds.crosswalk {
+		if e.System != system {
+			continue
+		}
+		if system == SystemONET && strings.EqualFold(e.Name, code) {
+			if group == nil {
+				group = &CrosswalkEntry{System: system, Code: e.Name, Refs: e.Refs}
+			}
+			group.Name = strings.TrimPrefix(group.Name+"; "+e.Code, "; ")
+			continue
+		}
+		if !strings.EqualFold(e.Code, code) {
+			continue
+		}
+		out = append(out, XrefMatch{CrosswalkEntry: e})
+	}
+	if group != nil {
+		out = append(out, XrefMatch{CrosswalkEntry: *group})
+	}
+	for i := range out {
+		e := out[i].CrosswalkEntry
+		acts, err := ds.ResolveRefs(e.Refs)
+		if err != nil {
+			return nil, fmt.Errorf("mappings.json: %s %s: %w", system, e.Code, err)
+		}
+		out[i].Activities = acts
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no %s code %q in mappings.json", system, asked)
+	}
+	return out, nil
+}
+
+// iscoSubMajorCounts is the number of sub-major groups in each ISCO-08
+// major group: 01-03, 11-14, 21-26 and so on.
+var iscoSubMajorCounts = [10]int{3, 4, 6, 5, 4, 4, 3, 5, 3, 6}
+
+// iscoSubMajorGroup returns the major group of an ISCO-08 sub-major group
+// code such as "42", or false if code is not one.
+func iscoSubMajorGroup(code string) (string, bool) {
+	if len(code) != 2 || code[0] < '0' || code[0] > '9' || code[1] < '1' || code[1] > '9' {
+		return "", false
+	}
+	if int(code[1]-'0') > iscoSubMajorCounts[code[0]-'0'] {
+		return "", false
+	}
+	return code[:1], true
+}
+
+// XrefCodes returns the codes of a system, for listing.
+func (ds *Dataset) XrefCodes(system string) []CrosswalkEntry {
+	var out []CrosswalkEntry
+	for _, e := range ds.crosswalk {
+		if e.System == strings.ToLower(system) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
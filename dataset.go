@@ -0,0 +1,330 @@
+// Package haai loads and queries the Human Activity Automation Index data files
+// (taxonomy, activities, indices, assessments and external mappings).
+package haai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errNoAssessments = errors.New("no assessment files found")
+
+// Dataset is a fully loaded copy of the HAAI data files rooted at one directory.
+// Activity scores are merged from indices/ and the latest assessment at load time.
+type Dataset struct {
+	dir        string
+	taxonomy   *Taxonomy
+	scoring    *Scoring
+	activities []Activity
+	indices    map[string]*IndexFile
+	indexOrder []string
+	asOf       string
+	assessment *AssessmentFile
+	assessFile string
+	mappings   *Mappings
+	crosswalk  []CrosswalkEntry
+	reverse    map[string][]ExternalRef // activity ID -> external codes
+
+	searchOnce sync.Once
+	search     *searchIndex
+}
+
+// Option configures Load.
+type Option func(*Dataset)
+
+// AsOf pins time-dependent scores to the newest assessment dated on or
+// before date (YYYY-MM-DD) instead of the latest one.
+func AsOf(date string) Option {
+	return func(ds *Dataset) {
+		ds.asOf = date
+	}
+}
+
+// Load reads the data files under dir (the directory containing taxonomy.json).
+func Load(dir string, opts ...Option) (*Dataset, error) {
+	ds := &Dataset{dir: dir}
+	for _, opt := range opts {
+		opt(ds)
+	}
+	if ds.asOf != "" {
+		if _, err := time.Parse("2006-01-02", ds.asOf); err != nil {
+			return nil, fmt.Errorf("invalid as-of date %q (want YYYY-MM-DD)", ds.asOf)
+		}
+	}
+
+	var t Taxonomy
+	if err := ds.loadJSON("taxonomy.json", &t); err != nil {
+		return nil, err
+	}
+	ds.taxonomy = &t
+
+	var m Mappings
+	if err := ds.loadJSON("mappings.json", &m); err != nil {
+		return nil, err
+	}
+	ds.mappings = &m
+
+	var s Scoring
+	if err := ds.loadJSON("scoring.json", &s); err != nil && !isMissing(err) {
+		return nil, err
+	}
+	ds.scoring = &s
+
+	// Missing index and assessment files are tolerated (older data layouts),
+	// but files that exist and fail to parse are reported. Run Lint for a
+	// full consistency check.
+	if err := ds.loadIndices(); err != nil {
+		return nil, err
+	}
+
+	assessment, err := ds.loadLatestAssessment()
+	if err != nil && !isMissing(err) {
+		return nil, err
+	}
+	ds.assessment = assessment
+
+	if ds.activities, err = ds.loadActivities(); err != nil {
+		return nil, err
+	}
+	if err := ds.validateEssentialityFactors(); err != nil {
+		return nil, err
+	}
+	ds.computeComposites()
+	ds.buildReverseCrosswalk()
+
+	return ds, nil
+}
+
+// Dir returns the directory the dataset was loaded from.
+func (ds *Dataset) Dir() string {
+	return ds.dir
+}
+
+// Taxonomy returns the parsed taxonomy.json.
+func (ds *Dataset) Taxonomy() *Taxonomy {
+	return ds.taxonomy
+}
+
+// Domains returns all domains in taxonomy order.
+func (ds *Dataset) Domains() []Domain {
+	return ds.taxonomy.Domains
+}
+
+// Domain returns the domain with the given ID.
+func (ds *Dataset) Domain(id int) (*Domain, bool) {
+	for i := range ds.taxonomy.Domains {
+		if ds.taxonomy.Domains[i].ID == id {
+			return &ds.taxonomy.Domains[i], true
+		}
+	}
+	return nil, false
+}
+
+// Categories returns all categories across domains in taxonomy order.
+func (ds *Dataset) Categories() []Category {
+	var all []Category
+	for _, d := range ds.taxonomy.Domains {
+		all = append(all, d.Categories...)
+	}
+	return all
+}
+
+// Category returns the category with the given ID (e.g. "3.3").
+func (ds *Dataset) Category(id string) (*Category, bool) {
+	for i := range ds.taxonomy.Domains {
+		d := &ds.taxonomy.Domains[i]
+		for j := range d.Categories {
+			if d.Categories[j].ID == id {
+				return &d.Categories[j], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Activities returns all activities with merged scores.
+func (ds *Dataset) Activities() []Activity {
+	return ds.activities
+}
+
+// Activity returns the activity with the given ID (e.g. "3.3.1").
+func (ds *Dataset) Activity(id string) (*Activity, bool) {
+	for i := range ds.activities {
+		if ds.activities[i].ID == id {
+			return &ds.activities[i], true
+		}
+	}
+	return nil, false
+}
+
+// Scoring returns the parsed scoring.json (empty if the file is absent).
+func (ds *Dataset) Scoring() *Scoring {
+	return ds.scoring
+}
+
+// Indices returns the loaded index files keyed by index ID.
+func (ds *Dataset) Indices() map[string]*IndexFile {
+	return ds.indices
+}
+
+// IndexIDs returns the loaded index IDs in scoring.json order, followed by
+// any additional files found in indices/.
+func (ds *Dataset) IndexIDs() []string {
+	return ds.indexOrder
+}
+
+// Index returns the index with the given ID (e.g. "abstraction").
+func (ds *Dataset) Index(name string) (*IndexFile, bool) {
+	idx, ok := ds.indices[name]
+	return idx, ok
+}
+
+// Assessment returns the assessment used for time-dependent scores, or nil if none was found.
+func (ds *Dataset) Assessment() *AssessmentFile {
+	return ds.assessment
+}
+
+// AssessmentFile returns the path, relative to Dir, of the assessment used
+// for time-dependent scores, or "" if none was found.
+func (ds *Dataset) AssessmentFile() string {
+	return ds.assessFile
+}
+
+// Mappings returns the parsed mappings.json.
+func (ds *Dataset) Mappings() *Mappings {
+	return ds.mappings
+}
+
+// DomainFromID returns the domain number encoded in an activity or category ID.
+func DomainFromID(id string) int {
+	n, _ := strconv.Atoi(strings.SplitN(id, ".", 2)[0])
+	return n
+}
+
+func (ds *Dataset) loadJSON(filename string, v any) error {
+	path := filepath.Join(ds.dir, filename)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return nil
+}
+
+// isMissing reports whether err means a data file or directory does not exist
+func isMissing(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, errNoAssessments)
+}
+
+func (ds *Dataset) loadActivities() ([]Activity, error) {
+	// First try to load from new activities.json format
+	var af ActivitiesFile
+	err := ds.loadJSON("activities.json", &af)
+	if err == nil {
+		// Successfully loaded v2.0.0 format
+		activities := af.Activities
+
+		// activities.json leaves domainId implicit in the ID prefix
+		for i := range activities {
+			if activities[i].DomainID == 0 {
+				activities[i].DomainID = DomainFromID(activities[i].ID)
+			}
+		}
+
+		// Older layouts keep some intrinsic scores in domain files only
+		ds.mergeLegacyScores(activities)
+
+		// Merge scores into activities
+		if err := ds.mergeScores(activities); err != nil {
+			return nil, err
+		}
+		return activities, nil
+	}
+	if !isMissing(err) {
+		return nil, err
+	}
+
+	// Fall back to loading directly from domain-X.json files
+	var all []Activity
+	for i := 1; i <= 10; i++ {
+		var df DomainFile
+		filename := fmt.Sprintf("activities/domain-%d.json", i)
+		if err := ds.loadJSON(filename, &df); err != nil {
+			if isMissing(err) {
+				continue // Skip missing files
+			}
+			return nil, err
+		}
+		// Set DomainID for each activity from the domain file
+		for j := range df.Activities {
+			df.Activities[j].DomainID = df.DomainID
+		}
+		all = append(all, df.Activities...)
+	}
+
+	// Index files, where present, take precedence over scores embedded in
+	// domain files; time-dependent assessments always come from assessments/
+	if err := ds.mergeScores(all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// loadLatestAssessment finds and loads the most recent assessment file from
+// assessments/, honouring the AsOf option
+func (ds *Dataset) loadLatestAssessment() (*AssessmentFile, error) {
+	latestFile, err := ds.latestAssessmentFile(ds.asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	var af AssessmentFile
+	if err := ds.loadJSON("assessments/"+latestFile, &af); err != nil {
+		return nil, err
+	}
+	ds.assessFile = "assessments/" + latestFile
+
+	return &af, nil
+}
+
+// latestAssessmentFile returns the name of the newest file in assessments/
+// dated on or before asOf (any date if asOf is empty)
+func (ds *Dataset) latestAssessmentFile(asOf string) (string, error) {
+	entries, err := os.ReadDir(filepath.Join(ds.dir, "assessments"))
+	if err != nil {
+		return "", err
+	}
+
+	// Find the most recent assessment file (sorted by name = date)
+	var latestFile string
+	found := false
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			found = true
+			if asOf != "" && strings.TrimSuffix(entry.Name(), ".json") > asOf {
+				continue
+			}
+			if entry.Name() > latestFile {
+				latestFile = entry.Name()
+			}
+		}
+	}
+
+	if !found {
+		return "", errNoAssessments
+	}
+	if latestFile == "" {
+		return "", fmt.Errorf("no assessment dated on or before %s", asOf)
+	}
+	return latestFile, nil
+}
@@ -0,0 +1,251 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// minutesPerDay is the length of a diary day
+const minutesPerDay = 24 * 60
+
+// Day profile sources
+const (
+	DaySourceDiary = "diary"
+	DaySourceATUS  = "atus"
+)
+
+// How a day slot was mapped to HAAI
+const (
+	SlotCategory   = "category"
+	SlotATUS       = "atus"
+	SlotClassified = "classified"
+	SlotUnmapped   = "unmapped"
+)
+
+// DaySlot is one time slot of a day and the HAAI activities it maps to.
+// Its minutes are split evenly over those activities.
+type DaySlot struct {
+	Time       string             `json:"time,omitempty"`
+	ATUSCode   string             `json:"atusCode,omitempty"`
+	Activity   string             `json:"activity"`
+	Minutes    float64            `json:"minutes"`
+	Mapping    string             `json:"mapping"` // category, atus, classified or unmapped
+	Refs       []HAAIRef          `json:"haaiRefs"`
+	Activities int                `json:"activities"`
+	Capability map[string]float64 `json:"capability"` // minutes per aiCapability level
+
+	// ParticipationRate is set for ATUS slots
+	ParticipationRate float64 `json:"participationRate,omitempty"`
+}
+
+// DayProfile is how the minutes of a day divide over AI capability levels
+// and the AGI waves in which they are expected to become automatable.
+// Minutes of activities that AI has not attempted or that are human-essential
+// count as human minutes instead of in a wave.
+type DayProfile struct {
+	Subject      string             `json:"subject"`
+	Source       string             `json:"source"` // diary or atus
+	TotalMinutes float64            `json:"totalMinutes"`
+	Unmapped     float64            `json:"unmappedMinutes"`
+	Human        float64            `json:"humanMinutes"` // not expected to be automated
+	Capability   map[string]float64 `json:"capability"`   // minutes per aiCapability level
+	Waves        map[int]float64    `json:"waves"`        // automatable minutes per agiWave
+	Slots        []DaySlot          `json:"slots"`
+
+	// Minutes are the minutes allocated to each activity ID
+	Minutes map[string]float64 `json:"-"`
+}
+
+// ReadTimeDiary reads a time diary in the shape of validation.json's
+// dayInLifeCoverageTest: either that object on its own or any file with a
+// dayInLifeCoverageTest key (so validation.json itself is a valid diary).
+func ReadTimeDiary(path string) (*DayInLifeCoverageTest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var wrapped struct {
+		DayInLifeCoverageTest *DayInLifeCoverageTest `json:"dayInLifeCoverageTest"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if wrapped.DayInLifeCoverageTest != nil {
+		return wrapped.DayInLifeCoverageTest, nil
+	}
+	var d DayInLifeCoverageTest
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(d.Activities) == 0 {
+		return nil, fmt.Errorf("%s: no activities in diary", path)
+	}
+	return &d, nil
+}
+
+// DiaryDay profiles a personal time diary. Each slot maps to its category,
+// else to its ATUS code's categories, else to the category the classifier
+// ranks first for its activity text. A slot without minutes lasts until the
+// next slot starts; the last one lasts until the first starts the next day.
+func (ds *Dataset) DiaryDay(d *DayInLifeCoverageTest) (*DayProfile, error) {
+	minutes, err := slotMinutes(d.Activities)
+	if err != nil {
+		return nil, err
+	}
+	atus := make(map[string]ATUSEntry)
+	for _, e := range ds.mappings.ATUSMapping.Mappings {
+		atus[e.ATUSCode] = e
+	}
+
+	p := newDayProfile(d.TestSubject, DaySourceDiary)
+	for i, e := range d.Activities {
+		slot := DaySlot{Time: e.Time, Activity: e.Activity, Minutes: minutes[i]}
+		name := strings.TrimSpace(e.Time + " " + e.Activity)
+		switch {
+		case e.Classification.CategoryID != "":
+			slot.Mapping = SlotCategory
+			slot.Refs = []HAAIRef{HAAIRef(e.Classification.CategoryID)}
+		case e.ATUSCode != "":
+			a, ok := atus[e.ATUSCode]
+			if !ok {
+				return nil, fmt.Errorf("%s: ATUS code %s is not in mappings.json", name, e.ATUSCode)
+			}
+			slot.Mapping = SlotATUS
+			slot.ATUSCode = e.ATUSCode
+			for _, c := range a.HAICategories {
+				slot.Refs = append(slot.Refs, HAAIRef(c))
+			}
+		default:
+			slot.Mapping = SlotUnmapped
+			if c := ds.Classify(e.Activity, 1); len(c.Candidates) > 0 {
+				slot.Mapping = SlotClassified
+				slot.Refs = []HAAIRef{HAAIRef(c.Candidates[0].Category.ID)}
+			}
+		}
+		if err := p.add(ds, slot); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return p, nil
+}
+
+// ATUSDay profiles the average day of the ATUS population, with minutes
+// allocated to activities as by AllocateATUS. avgMinutesPerDay is already
+// averaged over everyone, including people who did not do the activity, so
+// it is used as is; participationRate is kept on each slot to tell how
+// widely the minutes are shared.
+func (ds *Dataset) ATUSDay() (*DayProfile, error) {
+	alloc, err := ds.AllocateATUS(nil)
+	if err != nil {
+		return nil, fmt.Errorf("mappings.json: %w", err)
+	}
+	p := newDayProfile("ATUS population average", DaySourceATUS)
+	for i, e := range ds.mappings.ATUSMapping.Mappings {
+		slot := DaySlot{
+			ATUSCode:          e.ATUSCode,
+			Activity:          e.ATUSCategory,
+			Minutes:           float64(e.AvgMinutesPerDay),
+			Mapping:           SlotATUS,
+			ParticipationRate: e.ParticipationRate,
+		}
+		for _, c := range e.HAICategories {
+			slot.Refs = append(slot.Refs, HAAIRef(c))
+		}
+		p.addShares(ds, slot, alloc.Entries[i].Activities)
+	}
+	return p, nil
+}
+
+func newDayProfile(subject, source string) *DayProfile {
+	return &DayProfile{
+		Subject:    subject,
+		Source:     source,
+		Capability: make(map[string]float64),
+		Waves:      make(map[int]float64),
+		Minutes:    make(map[string]float64),
+	}
+}
+
+// add maps a slot to its activities and spreads its minutes evenly over them
+func (p *DayProfile) add(ds *Dataset, slot DaySlot) error {
+	acts, err := ds.ResolveRefs(slot.Refs)
+	if err != nil {
+		return err
+	}
+	shares := make(map[string]float64)
+	for _, a := range acts {
+		shares[a.ID] = slot.Minutes / float64(len(acts))
+	}
+	p.addShares(ds, slot, shares)
+	return nil
+}
+
+// addShares adds a slot whose minutes are already divided over activity IDs
+func (p *DayProfile) addShares(ds *Dataset, slot DaySlot, shares map[string]float64) {
+	slot.Capability = make(map[string]float64)
+	slot.Activities = len(shares)
+	p.TotalMinutes += slot.Minutes
+	allocated := 0.0
+	for _, id := range sortedKeys(shares) {
+		a, ok := ds.Activity(id)
+		if !ok {
+			continue
+		}
+		m := shares[id]
+		slot.Capability[a.Scores.AICapability] += m
+		p.Capability[a.Scores.AICapability] += m
+		if a.Scores.AICapability == "not_attempted" || ds.humanEssential(a) {
+			p.Human += m
+		} else {
+			p.Waves[a.Scores.AGIWave] += m
+		}
+		p.Minutes[a.ID] += m
+		allocated += m
+	}
+	if rest := slot.Minutes - allocated; rest > 1e-9 {
+		p.Unmapped += rest
+	}
+	p.Slots = append(p.Slots, slot)
+}
+
+// humanEssential reports whether at least half the weight of the
+// humanEssentiality factors applies to an activity
+func (ds *Dataset) humanEssential(a *Activity) bool {
+	e := ds.HumanEssentiality(a)
+	return e.Max > 0 && e.Score*2 >= e.Max
+}
+
+// slotMinutes returns each diary slot's length in minutes
+func slotMinutes(entries []DayInLifeEntry) ([]float64, error) {
+	starts := make([]int, len(entries))
+	for i, e := range entries {
+		starts[i] = -1
+		if t, err := time.Parse("15:04", strings.TrimSpace(e.Time)); err == nil {
+			starts[i] = t.Hour()*60 + t.Minute()
+		}
+	}
+
+	out := make([]float64, len(entries))
+	for i, e := range entries {
+		switch {
+		case e.Minutes < 0:
+			return nil, fmt.Errorf("%s %s: minutes must not be negative", e.Time, e.Activity)
+		case e.Minutes > 0:
+			out[i] = float64(e.Minutes)
+			continue
+		}
+		next := (i + 1) % len(entries)
+		if starts[i] < 0 || starts[next] < 0 {
+			return nil, fmt.Errorf("%s %s: give minutes, or HH:MM times for this slot and the next", e.Time, e.Activity)
+		}
+		d := starts[next] - starts[i]
+		if d <= 0 {
+			d += minutesPerDay
+		}
+		out[i] = float64(d)
+	}
+	return out, nil
+}
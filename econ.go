@@ -0,0 +1,213 @@
+package haai
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// EconTolerance is the relative difference between computed and projected
+// workers or value above which EconExposure reports a disagreement.
+const EconTolerance = 0.25
+
+// DomainExposure is a domain's workers and annual value with the share of its
+// activities at each capability level and AGI wave.
+type DomainExposure struct {
+	DomainID      int                `json:"domainId"`
+	Name          string             `json:"domainName"`
+	Workers       int                `json:"estimatedWorkers"`
+	ValueBillions float64            `json:"annualValueBillions"`
+	Exposure      string             `json:"automationExposure"` // hand-entered
+	Activities    int                `json:"activities"`
+	Capability    map[string]float64 `json:"capability"` // share of activities per aiCapability level
+	Waves         map[int]float64    `json:"waves"`      // share of activities per agiWave
+	PeakWave      int                `json:"peakWave"`   // wave with the largest share
+}
+
+// LevelExposure is the workers and value at one AI capability level.
+type LevelExposure struct {
+	Level         string  `json:"level"`
+	Workers       float64 `json:"workers"`
+	ValueBillions float64 `json:"valueBillions"`
+}
+
+// WaveExposure is the workers and value exposed in one AGI wave, computed
+// bottom-up, next to automationImpactProjections' figures for that wave.
+type WaveExposure struct {
+	Wave               int             `json:"wave"`
+	Workers            float64         `json:"workers"`
+	PercentOfWorkforce float64         `json:"percentOfWorkforce"`
+	ValueBillions      float64         `json:"valueBillions"`
+	Domains            []int           `json:"domains"` // domains whose peak wave this is
+	Projection         *WaveProjection `json:"projection,omitempty"`
+	Disagreements      []string        `json:"disagreements,omitempty"`
+}
+
+// EconExposure joins domainEconomics with the activity assessments: each
+// domain's workers and annual value are weighted by the share of its
+// activities at each capability level and wave, and the wave totals are
+// checked against automationImpactProjections.
+type EconExposure struct {
+	Currency   string           `json:"currency"`
+	Year       int              `json:"year"`
+	Tolerance  float64          `json:"tolerance"`
+	Domains    []DomainExposure `json:"domains"`
+	Capability []LevelExposure  `json:"capability"`
+	Waves      []WaveExposure   `json:"waves"`
+}
+
+// Disagreements returns every wave's disagreements, prefixed with the wave.
+func (e *EconExposure) Disagreements() []string {
+	var out []string
+	for _, w := range e.Waves {
+		for _, d := range w.Disagreements {
+			out = append(out, fmt.Sprintf("wave %d: %s", w.Wave, d))
+		}
+	}
+	return out
+}
+
+// EconExposure computes the bottom-up economic exposure. A wave's workers
+// or value disagree with the projection when they differ by more than
+// tolerance (relative to the projection); its primaryDomains disagree when
+// they are not the domains whose activities mostly fall in that wave.
+func (ds *Dataset) EconExposure(tolerance float64) (*EconExposure, error) {
+	if tolerance < 0 || math.IsNaN(tolerance) || math.IsInf(tolerance, 0) {
+		return nil, fmt.Errorf("tolerance must be a finite number of at least 0, got %g", tolerance)
+	}
+	econ := ds.mappings.EconomicImpact
+	e := &EconExposure{Currency: econ.Currency, Year: econ.Year, Tolerance: tolerance}
+
+	byDomain := make(map[int][]Activity)
+	for _, a := range ds.activities {
+		byDomain[a.DomainID] = append(byDomain[a.DomainID], a)
+	}
+
+	levels := make(map[string]*LevelExposure)
+	waves := make(map[int]*WaveExposure)
+	wave := func(n int) *WaveExposure {
+		if waves[n] == nil {
+			waves[n] = &WaveExposure{Wave: n}
+		}
+		return waves[n]
+	}
+	for _, d := range econ.DomainEcon {
+		de := DomainExposure{
+			DomainID:      d.DomainID,
+			Name:          d.DomainName,
+			Workers:       d.EstimatedWorkers,
+			ValueBillions: float64(d.AnnualValueBillions),
+			Exposure:      d.AutomationExposure,
+			Capability:    make(map[string]float64),
+			Waves:         make(map[int]float64),
+		}
+		for _, a := range byDomain[d.DomainID] {
+			de.Activities++
+			de.Capability[a.Scores.AICapability]++
+			if a.Scores.AGIWave > 0 {
+				de.Waves[a.Scores.AGIWave]++
+			}
+		}
+		for level, n := range de.Capability {
+			de.Capability[level] = n / float64(de.Activities)
+			if levels[level] == nil {
+				levels[level] = &LevelExposure{Level: level}
+			}
+			levels[level].Workers += de.Capability[level] * float64(de.Workers)
+			levels[level].ValueBillions += de.Capability[level] * de.ValueBillions
+		}
+		for n, count := range de.Waves {
+			de.Waves[n] = count / float64(de.Activities)
+			w := wave(n)
+			w.Workers += de.Waves[n] * float64(de.Workers)
+			w.ValueBillions += de.Waves[n] * de.ValueBillions
+			if de.Waves[n] > de.Waves[de.PeakWave] || (de.Waves[n] == de.Waves[de.PeakWave] && n < de.PeakWave) {
+				de.PeakWave = n
+			}
+		}
+		if de.PeakWave > 0 {
+			wave(de.PeakWave).Domains = append(wave(de.PeakWave).Domains, de.DomainID)
+		}
+		e.Domains = append(e.Domains, de)
+	}
+
+	for _, level := range ds.CapabilityLevels() {
+		if l := levels[level]; l != nil {
+			e.Capability = append(e.Capability, *l)
+		}
+	}
+
+	peaks := make(map[int]int)
+	for _, de := range e.Domains {
+		peaks[de.DomainID] = de.PeakWave
+	}
+	for i := range econ.Projections.Waves {
+		p := econ.Projections.Waves[i]
+		wave(p.Wave).Projection = &p
+	}
+	var ns []int
+	for n := range waves {
+		ns = append(ns, n)
+	}
+	sort.Ints(ns)
+	for _, n := range ns {
+		w := waves[n]
+		sort.Ints(w.Domains)
+		if total := econ.USLaborMarket.TotalEmployment; total > 0 {
+			w.PercentOfWorkforce = w.Workers / float64(total) * 100
+		}
+		w.Disagreements = w.compare(peaks, tolerance)
+		e.Waves = append(e.Waves, *w)
+	}
+	return e, nil
+}
+
+// compare lists where the computed wave differs from its projection
+func (w *WaveExposure) compare(peaks map[int]int, tolerance float64) []string {
+	p := w.Projection
+	if p == nil {
+		return []string{"no projection for this wave in automationImpactProjections"}
+	}
+	var out []string
+	if diff, ok := relativeDiff(w.Workers, float64(p.WorkersAffected), tolerance); ok {
+		out = append(out, fmt.Sprintf("workers: computed %.1fM, projected %.1fM (%+.0f%%)",
+			w.Workers/1e6, float64(p.WorkersAffected)/1e6, diff*100))
+	}
+	if diff, ok := relativeDiff(w.ValueBillions, float64(p.EconomicValueBillions), tolerance); ok {
+		out = append(out, fmt.Sprintf("value: computed $%.0fB, projected $%dB (%+.0f%%)",
+			w.ValueBillions, p.EconomicValueBillions, diff*100))
+	}
+	for _, d := range p.PrimaryDomains {
+		if peak, ok := peaks[d]; ok && peak != w.Wave {
+			out = append(out, fmt.Sprintf("domain %d is a primary domain, but most of its activities are in wave %d", d, peak))
+		}
+	}
+	for _, d := range w.Domains {
+		if !containsInt(p.PrimaryDomains, d) {
+			out = append(out, fmt.Sprintf("most of domain %d's activities are in this wave, but it is not a primary domain", d))
+		}
+	}
+	return out
+}
+
+// relativeDiff returns (computed-projected)/projected and whether it exceeds
+// tolerance. A zero projection disagrees with any non-zero computed figure.
+func relativeDiff(computed, projected, tolerance float64) (float64, bool) {
+	if projected == 0 {
+		if computed == 0 {
+			return 0, false
+		}
+		return math.Inf(1), true
+	}
+	diff := (computed - projected) / projected
+	return diff, math.Abs(diff) > tolerance
+}
+
+func containsInt(list []int, n int) bool {
+	for _, x := range list {
+		if x == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,253 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// fieldAliases maps short names accepted in rules and queries to canonical field names
+var fieldAliases = map[string]string{
+	"domain":     "domainId",
+	"category":   "categoryId",
+	"capability": "aiCapability",
+	"wave":       "agiWave",
+	"readiness":  "automationReadiness",
+}
+
+// canonicalField resolves aliases and index IDs (e.g. "error-tolerance") to field names
+func canonicalField(name string) string {
+	if c, ok := fieldAliases[name]; ok {
+		return c
+	}
+	switch name {
+	case "error-tolerance":
+		return "errorTolerance"
+	case "feedback-speed":
+		return "feedbackSpeed"
+	case "interpersonal-complexity":
+		return "interpersonalComplexity"
+	}
+	return name
+}
+
+// Field returns the value of a named activity field as float64 or string.
+// Names are the JSON names of Activity and Scores fields ("domainId",
+// "errorTolerance", "aiCapability", ...), the short aliases domain, category,
+// capability, wave and readiness, or the ID of any custom index.
+func (a *Activity) Field(name string) (any, bool) {
+	s := &a.Scores
+	switch canonicalField(name) {
+	case "id":
+		return a.ID, true
+	case "name":
+		return a.Name, true
+	case "description":
+		return a.Description, true
+	case "domainId":
+		if a.DomainID > 0 {
+			return float64(a.DomainID), true
+		}
+		return float64(DomainFromID(a.ID)), true
+	case "categoryId":
+		return a.CategoryID, true
+	case "abstraction":
+		return float64(s.Abstraction), true
+	case "errorTolerance":
+		return float64(s.ErrorTolerance), true
+	case "feedbackSpeed":
+		return float64(s.FeedbackSpeed), true
+	case "interpersonalComplexity":
+		return float64(s.InterpersonalComplexity), true
+	case "purpose":
+		return float64(s.Purpose), true
+	case "aiCapability":
+		return s.AICapability, true
+	case "bottleneck":
+		return s.Bottleneck, true
+	case "agiWave":
+		return float64(s.AGIWave), true
+	case "automationReadiness":
+		return s.AutomationReadiness, true
+	case "humanEssentiality":
+		return s.HumanEssentiality, true
+	}
+	if v, ok := s.Custom[name]; ok {
+		return float64(v), true
+	}
+	return nil, false
+}
+
+// HasField reports whether name is a field of every activity in the dataset.
+func (ds *Dataset) HasField(name string) bool {
+	var zero Activity
+	if _, ok := zero.Field(name); ok {
+		return true
+	}
+	_, ok := ds.indices[name]
+	return ok
+}
+
+// Condition is a predicate over activity fields. A leaf compares Field with
+// Value using Op; All and Any combine nested conditions.
+type Condition struct {
+	Field string          `json:"field,omitempty"`
+	Op    string          `json:"op,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+	All   []Condition     `json:"all,omitempty"`
+	Any   []Condition     `json:"any,omitempty"`
+}
+
+// Match reports whether the activity satisfies the condition. Conditions
+// should be checked with Dataset.ValidateCondition first; invalid leaves never match.
+func (c *Condition) Match(a *Activity) bool {
+	if len(c.All) > 0 || len(c.Any) > 0 {
+		for i := range c.All {
+			if !c.All[i].Match(a) {
+				return false
+			}
+		}
+		if len(c.Any) == 0 {
+			return true
+		}
+		for i := range c.Any {
+			if c.Any[i].Match(a) {
+				return true
+			}
+		}
+		return false
+	}
+
+	fv, ok := a.Field(c.Field)
+	if !ok {
+		return false
+	}
+	vals, err := c.values()
+	if err != nil {
+		return false
+	}
+	return compareField(fv, c.Op, vals)
+}
+
+// ValidateCondition checks that every leaf names a known field, a supported
+// operator and a value of the right shape.
+func (ds *Dataset) ValidateCondition(c *Condition) error {
+	if len(c.All) > 0 || len(c.Any) > 0 {
+		if c.Field != "" {
+			return fmt.Errorf("condition on %q cannot also have all/any", c.Field)
+		}
+		for i := range c.All {
+			if err := ds.ValidateCondition(&c.All[i]); err != nil {
+				return err
+			}
+		}
+		for i := range c.Any {
+			if err := ds.ValidateCondition(&c.Any[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !ds.HasField(c.Field) {
+		return fmt.Errorf("unknown field %q", c.Field)
+	}
+	vals, err := c.values()
+	if err != nil {
+		return fmt.Errorf("%s: invalid value: %w", c.Field, err)
+	}
+	return checkOperands(c.Op, vals)
+}
+
+// values decodes Value as a list of float64/string operands
+func (c *Condition) values() ([]any, error) {
+	var v any
+	if err := json.Unmarshal(c.Value, &v); err != nil {
+		return nil, err
+	}
+	if list, ok := v.([]any); ok {
+		return list, nil
+	}
+	return []any{v}, nil
+}
+
+func checkOperands(op string, vals []any) error {
+	switch op {
+	case "==", "!=":
+		if len(vals) != 1 {
+			return fmt.Errorf("operator %s needs a single value", op)
+		}
+	case "<", "<=", ">", ">=":
+		if len(vals) != 1 {
+			return fmt.Errorf("operator %s needs a single value", op)
+		}
+		if _, ok := vals[0].(float64); !ok {
+			return fmt.Errorf("operator %s needs a number", op)
+		}
+	case "in", "not_in":
+		if len(vals) == 0 {
+			return fmt.Errorf("operator %s needs at least one value", op)
+		}
+	case "between":
+		if len(vals) != 2 {
+			return fmt.Errorf("operator between needs [min, max]")
+		}
+		for _, v := range vals {
+			if _, ok := v.(float64); !ok {
+				return fmt.Errorf("operator between needs numbers")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown operator %q", op)
+	}
+	return nil
+}
+
+// compareField applies op to a field value and its operands
+func compareField(fv any, op string, vals []any) bool {
+	switch op {
+	case "==":
+		return equalValues(fv, vals[0])
+	case "!=":
+		return !equalValues(fv, vals[0])
+	case "in", "not_in":
+		found := false
+		for _, v := range vals {
+			if equalValues(fv, v) {
+				found = true
+				break
+			}
+		}
+		return found == (op == "in")
+	}
+
+	n, ok := fv.(float64)
+	if !ok {
+		return false
+	}
+	switch op {
+	case "<":
+		return n < vals[0].(float64)
+	case "<=":
+		return n <= vals[0].(float64)
+	case ">":
+		return n > vals[0].(float64)
+	case ">=":
+		return n >= vals[0].(float64)
+	case "between":
+		return n >= vals[0].(float64) && n <= vals[1].(float64)
+	}
+	return false
+}
+
+func equalValues(a, b any) bool {
+	switch x := a.(type) {
+	case float64:
+		y, ok := b.(float64)
+		return ok && x == y
+	case string:
+		y, ok := b.(string)
+		return ok && strings.EqualFold(x, y)
+	}
+	return false
+}
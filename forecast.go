@@ -0,0 +1,392 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// Forecast distribution sources
+const (
+	ForecastAssessed = "assessed" // forecast given in the assessment
+	ForecastDerived  = "derived"  // spread around agiWave by capability level
+)
+
+// DefaultOpenWindowYears is how long an open-ended wave window such as
+// "2032+" is taken to last when sampling years.
+const DefaultOpenWindowYears = 6
+
+// ForecastSpread is the chance that an activity arrives one wave earlier or
+// one wave later than its agiWave. Waves outside the timeline are clamped to
+// the first or last wave.
+type ForecastSpread struct {
+	Earlier float64 `json:"earlier"`
+	Later   float64 `json:"later"`
+}
+
+// DefaultForecastSpreads returns the spread used for each capability level
+// when an activity has no forecast: the further from solved, the less
+// certain and the more likely to slip.
+func DefaultForecastSpreads() map[string]ForecastSpread {
+	return map[string]ForecastSpread{
+		"solved":        {Earlier: 0, Later: 0.05},
+		"near_solved":   {Earlier: 0.05, Later: 0.15},
+		"partial":       {Earlier: 0.1, Later: 0.25},
+		"early":         {Earlier: 0.1, Later: 0.35},
+		"not_attempted": {Earlier: 0.05, Later: 0.45},
+	}
+}
+
+// ReadForecastSpreads reads spreads per capability level from a JSON file of
+// the form {"partial": {"earlier": 0.1, "later": 0.3}}.
+func ReadForecastSpreads(path string) (map[string]ForecastSpread, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spreads map[string]ForecastSpread
+	if err := json.Unmarshal(data, &spreads); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return spreads, nil
+}
+
+// Validate checks that a forecast is either a set of wave probabilities or
+// an ordered earliest/likely/latest triple.
+func (f *WaveForecast) Validate() error {
+	years := f.Earliest != 0 || f.Likely != 0 || f.Latest != 0
+	switch {
+	case len(f.WaveProbabilities) > 0 && years:
+		return fmt.Errorf("forecast has both waveProbabilities and years")
+	case len(f.WaveProbabilities) > 0:
+		var total float64
+		for w, p := range f.WaveProbabilities {
+			if p < 0 {
+				return fmt.Errorf("forecast probability for wave %d is negative", w)
+			}
+			total += p
+		}
+		if total == 0 {
+			return fmt.Errorf("forecast probabilities sum to 0")
+		}
+	case years:
+		if f.Earliest <= 0 || f.Earliest > f.Likely || f.Likely > f.Latest {
+			return fmt.Errorf("forecast years must satisfy 0 < earliest <= likely <= latest")
+		}
+	default:
+		return fmt.Errorf("forecast is empty")
+	}
+	return nil
+}
+
+// ForecastOptions configures Forecast.
+type ForecastOptions struct {
+	Samples         int                       // Monte Carlo runs, at least 1
+	Seed            int64                     // RNG seed, so runs can be repeated
+	Share           float64                   // share of a group that must be automated, in (0,1]
+	OpenWindowYears int                       // assumed length of an open window, at least 1
+	Spreads         map[string]ForecastSpread // override DefaultForecastSpreads per level
+}
+
+// DatePercentiles are the P10, P50 and P90 of a sampled automation date, in
+// fractional years.
+type DatePercentiles struct {
+	P10 float64 `json:"p10"`
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+}
+
+// ActivityForecast is the sampled automation date of one activity.
+type ActivityForecast struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	AGIWave  int           `json:"agiWave"`
+	Source   string        `json:"source"` // assessed or derived
+	Forecast *WaveForecast `json:"forecast"`
+	DatePercentiles
+}
+
+// GroupForecast is the sampled date by which a group's share of weighted
+// activities is automated.
+type GroupForecast struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Activities int    `json:"activities"`
+	DatePercentiles
+}
+
+// ForecastResult holds P10/P50/P90 automation dates for activities,
+// domains, ISCO occupation groups and the economic value in domainEconomics.
+type ForecastResult struct {
+	Samples     int                `json:"samples"`
+	Seed        int64              `json:"seed"`
+	Share       float64            `json:"share"`
+	Windows     []WaveWindow       `json:"windows"`
+	Activities  []ActivityForecast `json:"activities"`
+	Domains     []GroupForecast    `json:"domains"`
+	Occupations []GroupForecast    `json:"occupations"`
+	Value       GroupForecast      `json:"value"`
+}
+
+// Forecast samples every activity's automation date with a seeded RNG. An
+// activity's date comes from its assessed forecast, or else from its
+// agiWave spread by its capability level; a year is then drawn uniformly
+// from the wave's window. A group's date in a run is the date by which
+// opts.Share of its weighted activities are automated: activities weigh
+// equally in a domain, by iscoMapping reference in an occupation group and
+// by annual value per activity for the economy.
+func (ds *Dataset) Forecast(opts ForecastOptions) (*ForecastResult, error) {
+	af := ds.Assessment()
+	if af == nil {
+		return nil, fmt.Errorf("no assessment with agiWaveTimelines loaded")
+	}
+	if opts.Samples < 1 {
+		return nil, fmt.Errorf("samples must be at least 1, got %d", opts.Samples)
+	}
+	if opts.Share <= 0 || opts.Share > 1 {
+		return nil, fmt.Errorf("share must be above 0 and at most 1, got %g", opts.Share)
+	}
+	if opts.OpenWindowYears < 1 {
+		return nil, fmt.Errorf("open window years must be at least 1, got %d", opts.OpenWindowYears)
+	}
+	spreads := DefaultForecastSpreads()
+	for level, s := range opts.Spreads {
+		if !containsString(ds.CapabilityLevels(), level) {
+			return nil, fmt.Errorf("spreads: unknown capability level %q", level)
+		}
+		if s.Earlier < 0 || s.Later < 0 || s.Earlier+s.Later > 1 {
+			return nil, fmt.Errorf("spreads: %s: earlier and later must be non-negative and sum to at most 1", level)
+		}
+		spreads[level] = s
+	}
+
+	windows := af.WaveWindows()
+	res := &ForecastResult{Samples: opts.Samples, Seed: opts.Seed, Share: opts.Share}
+	var waves []int
+	for w, win := range windows {
+		waves = append(waves, w)
+		res.Windows = append(res.Windows, win)
+	}
+	if len(waves) == 0 {
+		return nil, fmt.Errorf("assessment has no parseable agiWaveTimelines")
+	}
+	sort.Ints(waves)
+	sort.Slice(res.Windows, func(i, j int) bool { return res.Windows[i].Wave < res.Windows[j].Wave })
+
+	// One distribution per activity
+	entries := af.Entries()
+	for _, a := range ds.activities {
+		fa := ActivityForecast{ID: a.ID, Name: a.Name, AGIWave: a.Scores.AGIWave, Source: ForecastAssessed}
+		if aa, ok := entries[a.ID]; ok && aa.Forecast != nil {
+			if err := aa.Forecast.Validate(); err != nil {
+				return nil, fmt.Errorf("%s: %w", a.ID, err)
+			}
+			for w := range aa.Forecast.WaveProbabilities {
+				if _, ok := windows[w]; !ok {
+					return nil, fmt.Errorf("%s: forecast wave %d has no agiWaveTimelines window", a.ID, w)
+				}
+			}
+			fa.Forecast = aa.Forecast
+		} else {
+			if _, ok := windows[a.Scores.AGIWave]; !ok {
+				return nil, fmt.Errorf("%s: agiWave %d has no agiWaveTimelines window", a.ID, a.Scores.AGIWave)
+			}
+			fa.Source = ForecastDerived
+			fa.Forecast = derivedForecast(a.Scores.AGIWave, spreads[a.Scores.AICapability], waves)
+		}
+		res.Activities = append(res.Activities, fa)
+	}
+
+	// Group weights over activity indexes
+	index := make(map[string]int)
+	for i, a := range ds.activities {
+		index[a.ID] = i
+	}
+	type group struct {
+		forecast *GroupForecast
+		weights  []weightedIndex
+	}
+	var groups []group
+	addGroup := func(g *GroupForecast, weights map[int]float64) {
+		var w []weightedIndex
+		for i, x := range weights {
+			if x > 0 {
+				w = append(w, weightedIndex{i, x})
+			}
+		}
+		sort.Slice(w, func(i, j int) bool { return w[i].index < w[j].index })
+		g.Activities = len(w)
+		groups = append(groups, group{g, w})
+	}
+	domainWeights := make(map[int]map[int]float64)
+	for i, a := range ds.activities {
+		if domainWeights[a.DomainID] == nil {
+			domainWeights[a.DomainID] = make(map[int]float64)
+		}
+		domainWeights[a.DomainID][i] = 1
+	}
+	for _, d := range ds.Domains() {
+		res.Domains = append(res.Domains, GroupForecast{ID: strconv.Itoa(d.ID), Name: d.Name})
+	}
+	for i, d := range ds.Domains() {
+		addGroup(&res.Domains[i], domainWeights[d.ID])
+	}
+	iscoWeights := make([]map[int]float64, len(ds.mappings.ISCOMapping.Mappings))
+	for i, e := range ds.mappings.ISCOMapping.Mappings {
+		res.Occupations = append(res.Occupations, GroupForecast{ID: strconv.Itoa(e.ISCOGroup), Name: e.ISCOName})
+		iscoWeights[i] = make(map[int]float64)
+		for _, r := range e.HAAICategories {
+			acts, err := ds.ResolveRef(r)
+			if err != nil {
+				return nil, fmt.Errorf("mappings.json: ISCO group %d: %w", e.ISCOGroup, err)
+			}
+			for _, a := range acts {
+				iscoWeights[i][index[a.ID]] += 1 / float64(len(acts))
+			}
+		}
+	}
+	for i := range res.Occupations {
+		addGroup(&res.Occupations[i], iscoWeights[i])
+	}
+	res.Value = GroupForecast{ID: "value", Name: "Annual value in domainEconomics"}
+	valueWeights := make(map[int]float64)
+	for _, d := range ds.mappings.EconomicImpact.DomainEcon {
+		for i := range domainWeights[d.DomainID] {
+			if d.AnnualValueBillions > 0 {
+				valueWeights[i] = float64(d.AnnualValueBillions) / float64(len(domainWeights[d.DomainID]))
+			}
+		}
+	}
+	addGroup(&res.Value, valueWeights)
+
+	// Sample
+	rng := rand.New(rand.NewSource(opts.Seed))
+	actDates := make([][]float64, len(res.Activities))
+	groupDates := make([][]float64, len(groups))
+	dates := make([]float64, len(res.Activities))
+	for run := 0; run < opts.Samples; run++ {
+		for i, fa := range res.Activities {
+			dates[i] = fa.Forecast.sample(rng, windows, opts.OpenWindowYears)
+			actDates[i] = append(actDates[i], dates[i])
+		}
+		for g, gr := range groups {
+			if d, ok := weightedQuantile(dates, gr.weights, opts.Share); ok {
+				groupDates[g] = append(groupDates[g], d)
+			}
+		}
+	}
+	for i := range res.Activities {
+		res.Activities[i].DatePercentiles = datePercentiles(actDates[i])
+	}
+	for g, gr := range groups {
+		gr.forecast.DatePercentiles = datePercentiles(groupDates[g])
+	}
+	return res, nil
+}
+
+// derivedForecast spreads a point wave over its neighbours
+func derivedForecast(wave int, s ForecastSpread, waves []int) *WaveForecast {
+	f := &WaveForecast{WaveProbabilities: make(map[int]float64)}
+	clamp := func(w int) int {
+		return min(max(w, waves[0]), waves[len(waves)-1])
+	}
+	f.WaveProbabilities[clamp(wave-1)] += s.Earlier
+	f.WaveProbabilities[clamp(wave+1)] += s.Later
+	f.WaveProbabilities[wave] += 1 - s.Earlier - s.Later
+	return f
+}
+
+// sample draws an automation date in fractional years
+func (f *WaveForecast) sample(rng *rand.Rand, windows map[int]WaveWindow, openYears int) float64 {
+	if len(f.WaveProbabilities) == 0 {
+		return triangular(rng.Float64(), f.Earliest, f.Likely, f.Latest)
+	}
+	var waves []int
+	for w := range f.WaveProbabilities {
+		waves = append(waves, w)
+	}
+	sort.Ints(waves)
+	var total float64
+	for _, w := range waves {
+		total += f.WaveProbabilities[w]
+	}
+	u := rng.Float64() * total
+	wave := waves[len(waves)-1]
+	for _, w := range waves {
+		if u < f.WaveProbabilities[w] {
+			wave = w
+			break
+		}
+		u -= f.WaveProbabilities[w]
+	}
+	win := windows[wave]
+	end := float64(win.End + 1)
+	if win.End == 0 {
+		end = float64(win.Start + openYears)
+	}
+	return float64(win.Start) + rng.Float64()*(end-float64(win.Start))
+}
+
+// triangular maps u in [0,1) onto a triangular distribution
+func triangular(u, lo, mode, hi float64) float64 {
+	if hi == lo {
+		return lo
+	}
+	if c := (mode - lo) / (hi - lo); u < c {
+		return lo + math.Sqrt(u*(hi-lo)*(mode-lo))
+	}
+	return hi - math.Sqrt((1-u)*(hi-lo)*(hi-mode))
+}
+
+// weightedIndex is an activity's index in ds.activities and its weight in a group
+type weightedIndex struct {
+	index  int
+	weight float64
+}
+
+// weightedQuantile returns the earliest date by which share of the total
+// weight is automated
+func weightedQuantile(dates []float64, weights []weightedIndex, share float64) (float64, bool) {
+	type point struct{ date, weight float64 }
+	points := make([]point, 0, len(weights))
+	var total float64
+	for _, w := range weights {
+		points = append(points, point{dates[w.index], w.weight})
+		total += w.weight
+	}
+	if total == 0 {
+		return 0, false
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].date < points[j].date })
+	var cum float64
+	for _, p := range points {
+		cum += p.weight
+		if cum >= share*total-1e-9 {
+			return p.date, true
+		}
+	}
+	return points[len(points)-1].date, true
+}
+
+// datePercentiles returns P10, P50 and P90 of the samples, interpolating
+// between ranks
+func datePercentiles(samples []float64) DatePercentiles {
+	if len(samples) == 0 {
+		return DatePercentiles{}
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	at := func(p float64) float64 {
+		pos := p * float64(len(sorted)-1)
+		lo := int(pos)
+		if lo+1 >= len(sorted) {
+			return sorted[lo]
+		}
+		return sorted[lo] + (pos-float64(lo))*(sorted[lo+1]-sorted[lo])
+	}
+	return DatePercentiles{P10: at(0.1), P50: at(0.5), P90: at(0.9)}
+}
@@ -0,0 +1,167 @@
+package haai
+
+import (
+	"fmt"
+	"time"
+)
+
+// HistoryPoint is one activity's assessment in a single snapshot.
+type HistoryPoint struct {
+	Date       string             `json:"date"`
+	Assessment ActivityAssessment `json:"assessment"`
+	Present    bool               `json:"present"`
+	Changed    []string           `json:"changed,omitempty"`
+}
+
+// StateSpan is a run of consecutive snapshots at the same capability level.
+// Days runs to the next change, or to the last snapshot while Ongoing.
+type StateSpan struct {
+	Capability string `json:"capability"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Days       int    `json:"days"`
+	Ongoing    bool   `json:"ongoing"`
+}
+
+// PredictionCheck compares the wave predicted in the first snapshot with the
+// date the activity was first assessed as solved.
+type PredictionCheck struct {
+	PredictedWave int        `json:"predictedWave"`
+	Window        WaveWindow `json:"window"`
+	SolvedOn      string     `json:"solvedOn,omitempty"`
+	Verdict       string     `json:"verdict"` // on_time, early, late, overdue or pending
+}
+
+// ActivityHistory is an activity's capability timeline across all assessments.
+type ActivityHistory struct {
+	ID     string         `json:"id"`
+	Name   string         `json:"name"`
+	Points []HistoryPoint `json:"points"`
+	States []StateSpan    `json:"states"`
+
+	// LevelsPerYear is the net capability levels gained per year between the
+	// first and last snapshot (0 with fewer than two dated snapshots).
+	LevelsPerYear float64          `json:"levelsPerYear"`
+	Prediction    *PredictionCheck `json:"prediction,omitempty"`
+}
+
+// ActivityHistory walks every snapshot in assessments/ in date order (up to
+// the AsOf date, if set) and collects the activity's assessment in each.
+func (ds *Dataset) ActivityHistory(id string) (*ActivityHistory, error) {
+	act, ok := ds.Activity(id)
+	if !ok {
+		return nil, fmt.Errorf("activity %s not found", id)
+	}
+	dates, err := ds.AssessmentDates()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &ActivityHistory{ID: act.ID, Name: act.Name}
+	var first *AssessmentFile
+	var prev *HistoryPoint
+	for _, date := range dates {
+		if ds.asOf != "" && date > ds.asOf {
+			break
+		}
+		af, err := ds.LoadAssessment(date)
+		if err != nil {
+			return nil, err
+		}
+		aa, present := af.Entries()[id]
+		p := HistoryPoint{Date: date, Assessment: aa, Present: present}
+		if present && first == nil {
+			first = af
+		}
+		if prev != nil && prev.Present && present {
+			if prev.Assessment.AICapability != aa.AICapability {
+				p.Changed = append(p.Changed, "aiCapability")
+			}
+			if prev.Assessment.Bottleneck != aa.Bottleneck {
+				p.Changed = append(p.Changed, "bottleneck")
+			}
+			if prev.Assessment.AGIWave != aa.AGIWave {
+				p.Changed = append(p.Changed, "agiWave")
+			}
+		}
+		h.Points = append(h.Points, p)
+		prev = &h.Points[len(h.Points)-1]
+	}
+
+	var present []HistoryPoint
+	for _, p := range h.Points {
+		if p.Present {
+			present = append(present, p)
+		}
+	}
+	if len(present) == 0 {
+		return h, nil
+	}
+
+	for _, p := range present {
+		n := len(h.States)
+		if n > 0 && h.States[n-1].Capability == p.Assessment.AICapability {
+			continue
+		}
+		if n > 0 {
+			h.States[n-1].To = p.Date
+			h.States[n-1].Days = daysBetween(h.States[n-1].From, p.Date)
+			h.States[n-1].Ongoing = false
+		}
+		h.States = append(h.States, StateSpan{Capability: p.Assessment.AICapability, From: p.Date, Ongoing: true})
+	}
+	last := present[len(present)-1]
+	cur := &h.States[len(h.States)-1]
+	cur.To = last.Date
+	cur.Days = daysBetween(cur.From, last.Date)
+
+	if days := daysBetween(present[0].Date, last.Date); days > 0 {
+		gained := ds.capabilityRank(last.Assessment.AICapability) - ds.capabilityRank(present[0].Assessment.AICapability)
+		h.LevelsPerYear = float64(gained) / (float64(days) / 365.25)
+	}
+
+	firstEntry := present[0].Assessment
+	if win, ok := first.WaveWindows()[firstEntry.AGIWave]; ok {
+		check := &PredictionCheck{PredictedWave: firstEntry.AGIWave, Window: win, Verdict: "pending"}
+		for _, p := range present {
+			if p.Assessment.AICapability == "solved" {
+				check.SolvedOn = p.Date
+				break
+			}
+		}
+		switch year := yearOf(check.SolvedOn); {
+		case check.SolvedOn == "":
+			if win.End != 0 && yearOf(last.Date) > win.End {
+				check.Verdict = "overdue"
+			}
+		case win.Contains(year):
+			check.Verdict = "on_time"
+		case year < win.Start:
+			check.Verdict = "early"
+		default:
+			check.Verdict = "late"
+		}
+		h.Prediction = check
+	}
+
+	return h, nil
+}
+
+// daysBetween returns the number of days from a to b (YYYY-MM-DD), or 0 if either is invalid
+func daysBetween(a, b string) int {
+	ta, err1 := time.Parse("2006-01-02", a)
+	tb, err2 := time.Parse("2006-01-02", b)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(tb.Sub(ta).Hours() / 24)
+}
+
+// yearOf returns the year of a YYYY-MM-DD date, or 0 if it is invalid
+func yearOf(date string) int {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return 0
+	}
+	return t.Year()
+}
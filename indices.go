@@ -0,0 +1,189 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// builtinIndices maps the index IDs of the v2.0.0 data model to the Scores
+// field they populate. Any other index is merged into Scores.Custom.
+var builtinIndices = map[string]func(*Scores) *int{
+	"abstraction":              func(s *Scores) *int { return &s.Abstraction },
+	"error-tolerance":          func(s *Scores) *int { return &s.ErrorTolerance },
+	"feedback-speed":           func(s *Scores) *int { return &s.FeedbackSpeed },
+	"interpersonal-complexity": func(s *Scores) *int { return &s.InterpersonalComplexity },
+	"purpose":                  func(s *Scores) *int { return &s.Purpose },
+}
+
+// IndexValue returns the value of the given index for these scores.
+func (s *Scores) IndexValue(indexID string) (int, bool) {
+	if field, ok := builtinIndices[indexID]; ok {
+		return *field(s), true
+	}
+	v, ok := s.Custom[indexID]
+	return v, ok
+}
+
+// IndexOrdinal reports whether the levels of an index are ordered, i.e. its
+// scoring.json attribute is not marked "nominal".
+func (ds *Dataset) IndexOrdinal(indexID string) bool {
+	for _, attr := range ds.scoring.Attributes {
+		if attr.ShortName == canonicalField(indexID) {
+			return attr.Type != "nominal"
+		}
+	}
+	return true
+}
+
+// setIndexValue stores an index value in its dedicated field or in Custom
+func (s *Scores) setIndexValue(indexID string, v int) {
+	if field, ok := builtinIndices[indexID]; ok {
+		*field(s) = v
+		return
+	}
+	if s.Custom == nil {
+		s.Custom = make(map[string]int)
+	}
+	s.Custom[indexID] = v
+}
+
+// loadIndices loads every index listed in scoring.json's dataModel.indices,
+// then any other *.json file in indices/ so custom indices need no registration.
+func (ds *Dataset) loadIndices() error {
+	ds.indices = make(map[string]*IndexFile)
+	ds.indexOrder = nil
+
+	seen := make(map[string]bool)
+	load := func(file, fallbackID string) error {
+		if seen[filepath.Clean(file)] {
+			return nil
+		}
+		seen[filepath.Clean(file)] = true
+
+		var idx IndexFile
+		if err := ds.loadJSON(file, &idx); err != nil {
+			if isMissing(err) {
+				return nil // Skip missing files
+			}
+			return err
+		}
+		if idx.IndexID == "" {
+			idx.IndexID = fallbackID
+		}
+		if _, dup := ds.indices[idx.IndexID]; dup {
+			return fmt.Errorf("%s: duplicate indexId %q", file, idx.IndexID)
+		}
+		ds.indices[idx.IndexID] = &idx
+		ds.indexOrder = append(ds.indexOrder, idx.IndexID)
+		return nil
+	}
+
+	for _, ref := range ds.scoring.DataModel.Indices {
+		file := ref.File
+		if file == "" {
+			file = fmt.Sprintf("indices/%s.json", ref.IndexID)
+		}
+		if err := load(file, ref.IndexID); err != nil {
+			return err
+		}
+	}
+
+	indicesDir := ds.scoring.DataModel.IndicesDir
+	if indicesDir == "" {
+		indicesDir = "indices/"
+	}
+	entries, err := os.ReadDir(filepath.Join(ds.dir, indicesDir))
+	if err != nil {
+		if isMissing(err) {
+			return nil
+		}
+		return err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := load(filepath.Join(indicesDir, name), strings.TrimSuffix(name, ".json")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeLegacyScores fills intrinsic scores from activities/domain-N.json for
+// built-in indices that have no file in indices/ (the pre-2.0.0 layout kept
+// feedbackSpeed and interpersonalComplexity there).
+func (ds *Dataset) mergeLegacyScores(activities []Activity) {
+	var missing []string
+	for id := range builtinIndices {
+		if _, ok := ds.indices[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	legacy := make(map[string]Scores)
+	for i := 1; i <= 10; i++ {
+		var df DomainFile
+		filename := fmt.Sprintf("activities/domain-%d.json", i)
+		if err := ds.loadJSON(filename, &df); err != nil {
+			continue
+		}
+		for _, a := range df.Activities {
+			legacy[a.ID] = a.Scores
+		}
+	}
+
+	for i := range activities {
+		s, ok := legacy[activities[i].ID]
+		if !ok {
+			continue
+		}
+		for _, id := range missing {
+			field := builtinIndices[id]
+			*field(&activities[i].Scores) = *field(&s)
+		}
+	}
+}
+
+// mergeScores merges all score data into activity Scores structs:
+// - Intrinsic indices from every loaded file in indices/
+// - Time-dependent assessments (aiCapability, bottleneck, agiWave) from assessments/
+//
+// A malformed assessment entry is an error.
+func (ds *Dataset) mergeScores(activities []Activity) error {
+	for i := range activities {
+		id := activities[i].ID
+
+		// Intrinsic indices from indices/
+		for indexID, idx := range ds.indices {
+			if val, ok := idx.Values[id]; ok {
+				activities[i].Scores.setIndexValue(indexID, val)
+			}
+		}
+
+		// Time-dependent assessments from assessments/ (these change as AI evolves)
+		if ds.assessment != nil {
+			if raw, ok := ds.assessment.ActivityAssessments[id]; ok {
+				var aa ActivityAssessment
+				if err := json.Unmarshal(raw, &aa); err != nil {
+					return fmt.Errorf("%s: %s: %w", ds.assessFile, id, err)
+				}
+				activities[i].Scores.AICapability = aa.AICapability
+				activities[i].Scores.Bottleneck = aa.Bottleneck
+				activities[i].Scores.AGIWave = aa.AGIWave
+			}
+		}
+	}
+	return nil
+}
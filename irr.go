@@ -0,0 +1,365 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RatingSheet is a blinded sheet for inter-rater reliability testing. Items
+// show only an activity's name, description and example tasks; the rater
+// fills in each item's Ratings using the values listed in Scales.
+type RatingSheet struct {
+	Rater        string        `json:"rater"`
+	Seed         int64         `json:"seed"`
+	Instructions string        `json:"instructions"`
+	Scales       []RatingField `json:"scales"`
+	Items        []RatingItem  `json:"items"`
+}
+
+// RatingField describes one rated field and its allowed values.
+type RatingField struct {
+	Field   string             `json:"field"`
+	Name    string             `json:"name"`
+	Ordinal bool               `json:"ordinal"`
+	Values  []RatingFieldValue `json:"values"`
+}
+
+type RatingFieldValue struct {
+	Value any    `json:"value"` // string category ID or integer index level
+	Label string `json:"label"`
+}
+
+// RatingItem is one activity to rate. Ratings maps each field to the rater's
+// value, null until rated.
+type RatingItem struct {
+	Item         int            `json:"item"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	ExampleTasks []string       `json:"exampleTasks"`
+	Ratings      map[string]any `json:"ratings"`
+}
+
+// ratingCategoryField is the rated field for category assignment
+const ratingCategoryField = "categoryId"
+
+// RatingFields returns the fields rated in inter-rater reliability testing:
+// category assignment followed by every index.
+func (ds *Dataset) RatingFields() []RatingField {
+	cats := RatingField{Field: ratingCategoryField, Name: "Category"}
+	for _, c := range ds.Categories() {
+		cats.Values = append(cats.Values, RatingFieldValue{Value: c.ID, Label: c.Name})
+	}
+	fields := []RatingField{cats}
+	for _, id := range ds.IndexIDs() {
+		idx := ds.indices[id]
+		f := RatingField{Field: id, Name: idx.IndexName, Ordinal: ds.IndexOrdinal(id)}
+		for level := idx.Scale.Min; level <= idx.Scale.Max; level++ {
+			label := ""
+			for _, l := range idx.Scale.Levels {
+				if l.Level == level {
+					label = l.Name
+				}
+			}
+			f.Values = append(f.Values, RatingFieldValue{Value: level, Label: label})
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// SampleRatingSheet draws n activities at random (all of them if n <= 0 or
+// n exceeds the count) into a blank, blinded rating sheet. The same seed
+// always draws the same activities in the same order.
+func (ds *Dataset) SampleRatingSheet(n int, seed int64) *RatingSheet {
+	if n <= 0 || n > len(ds.activities) {
+		n = len(ds.activities)
+	}
+	fields := ds.RatingFields()
+	sheet := &RatingSheet{
+		Seed: seed,
+		Instructions: "Enter your name as rater. For every item, replace each null in ratings with one value " +
+			"from scales, judging from the description alone. Do not consult the HAAI data files or other raters.",
+		Scales: fields,
+	}
+	for i, pos := range rand.New(rand.NewSource(seed)).Perm(len(ds.activities))[:n] {
+		a := ds.activities[pos]
+		item := RatingItem{
+			Item:         i + 1,
+			Name:         a.Name,
+			Description:  a.Description,
+			ExampleTasks: a.ExampleTasks,
+			Ratings:      make(map[string]any),
+		}
+		for _, f := range fields {
+			item.Ratings[f.Field] = nil
+		}
+		sheet.Items = append(sheet.Items, item)
+	}
+	return sheet
+}
+
+// Encode renders the sheet as indented JSON with each scale value, example
+// task list and rating set on one line, so a rater can fill it in by hand.
+func (s *RatingSheet) Encode() ([]byte, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	root, err := parseOrdered(data)
+	if err != nil {
+		return nil, err
+	}
+	expand(root)
+	if scales, ok := root.values["scales"].(*orderedArray); ok {
+		for _, f := range scales.items {
+			if values, ok := f.(*orderedObject).values["values"].(*orderedArray); ok {
+				for _, v := range values.items {
+					v.(*orderedObject).inline = true
+				}
+			}
+		}
+	}
+	if items, ok := root.values["items"].(*orderedArray); ok {
+		for _, it := range items.items {
+			item := it.(*orderedObject)
+			if tasks, ok := item.values["exampleTasks"].(*orderedArray); ok {
+				tasks.inline = true
+			}
+			if ratings := item.Object("ratings"); ratings != nil {
+				ratings.inline = true
+				var keys []string
+				for _, f := range s.Scales {
+					if _, ok := ratings.values[f.Field]; ok {
+						keys = append(keys, f.Field)
+					}
+				}
+				if len(keys) == len(ratings.keys) {
+					ratings.keys = keys
+				}
+			}
+		}
+	}
+	return encodeOrdered(root)
+}
+
+// ReadRatingSheet reads a filled-in rating sheet. A sheet without a rater
+// name is named after its file.
+func ReadRatingSheet(path string) (*RatingSheet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sheet RatingSheet
+	if err := json.Unmarshal(data, &sheet); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if strings.TrimSpace(sheet.Rater) == "" {
+		sheet.Rater = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return &sheet, nil
+}
+
+// FieldAgreement is the agreement between raters on one field. Statistics
+// that cannot be computed (e.g. Cohen's kappa for three raters) are nil.
+type FieldAgreement struct {
+	Field            string   `json:"field"`
+	Ordinal          bool     `json:"ordinal"`
+	Units            int      `json:"units"` // items rated by at least two raters
+	PercentAgreement *float64 `json:"percentAgreement"`
+	CohensKappa      *float64 `json:"cohensKappa,omitempty"`
+	FleissKappa      *float64 `json:"fleissKappa,omitempty"`
+	Alpha            *float64 `json:"krippendorffAlpha"`
+	MeetsThreshold   bool     `json:"meetsThreshold"`
+}
+
+// Kappa returns Cohen's kappa for two raters, Fleiss' kappa for more.
+func (f FieldAgreement) Kappa() *float64 {
+	if f.CohensKappa != nil {
+		return f.CohensKappa
+	}
+	return f.FleissKappa
+}
+
+// Disagreement is an activity the raters disagreed on. Score sums, over the
+// fields, the mean distance between rater pairs scaled to 0-1.
+type Disagreement struct {
+	Activity Activity            `json:"activity"`
+	Score    float64             `json:"score"`
+	Fields   []FieldDisagreement `json:"fields"`
+}
+
+// FieldDisagreement lists each rater's value for a field they disagreed on.
+type FieldDisagreement struct {
+	Field  string   `json:"field"`
+	Values []string `json:"values"` // parallel to IRRReport.Raters, "" if unrated
+}
+
+// IRRReport is the inter-rater reliability of a set of rating sheets.
+type IRRReport struct {
+	Raters        []string         `json:"raters"`
+	Items         int              `json:"items"`
+	Threshold     float64          `json:"threshold"`
+	Fields        []FieldAgreement `json:"fields"`
+	Disagreements []Disagreement   `json:"disagreements"`
+}
+
+// defaultKappaThreshold is used when validation.json sets no threshold
+const defaultKappaThreshold = 0.7
+
+// ScoreRatings computes agreement between two or more rating sheets for
+// category assignment and every index: percent agreement, Cohen's kappa
+// (two raters; quadratic weights for ordinal indices), Fleiss' kappa (three
+// or more raters) and Krippendorff's alpha (nominal or ordinal metric).
+// Items are matched to activities by name; an item whose name several
+// activities share is an error. A field meets the threshold from
+// validation.json's interRaterReliability when its kappa reaches it.
+func (ds *Dataset) ScoreRatings(sheets []*RatingSheet) (*IRRReport, error) {
+	if len(sheets) < 2 {
+		return nil, fmt.Errorf("need at least two rating sheets, got %d", len(sheets))
+	}
+	report := &IRRReport{Threshold: defaultKappaThreshold}
+	if vf, err := ds.ValidationFile(); err == nil && vf.ValidationProtocol.InterRaterReliability.Threshold > 0 {
+		report.Threshold = vf.ValidationProtocol.InterRaterReliability.Threshold
+	}
+
+	fields := ds.RatingFields()
+	scales := make([]ratingScale, len(fields))
+	for i, f := range fields {
+		scales[i].ordinal = f.Ordinal
+		for _, v := range f.Values {
+			scales[i].values = append(scales[i].values, ratingValue(v.Value))
+		}
+	}
+
+	// Sheets only carry names, so a name shared by two activities cannot be
+	// matched
+	byName := make(map[string]int)
+	shared := make(map[string][]string)
+	for i, a := range ds.activities {
+		name := strings.ToLower(a.Name)
+		if prev, dup := byName[name]; dup {
+			if shared[name] == nil {
+				shared[name] = []string{ds.activities[prev].ID}
+			}
+			shared[name] = append(shared[name], a.ID)
+		}
+		byName[name] = i
+	}
+
+	// codes[activity][field][rater]
+	codes := make(map[int][][]int)
+	var order []int
+	for r, sheet := range sheets {
+		report.Raters = append(report.Raters, sheet.Rater)
+		for _, item := range sheet.Items {
+			name := strings.ToLower(strings.TrimSpace(item.Name))
+			if ids := shared[name]; ids != nil {
+				return nil, fmt.Errorf("%s: item %d %q is ambiguous: activities %s share the name", sheet.Rater, item.Item, item.Name, strings.Join(ids, ", "))
+			}
+			pos, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("%s: item %d %q is not an activity", sheet.Rater, item.Item, item.Name)
+			}
+			if codes[pos] == nil {
+				codes[pos] = make([][]int, len(fields))
+				for f := range fields {
+					codes[pos][f] = make([]int, len(sheets))
+					for i := range sheets {
+						codes[pos][f][i] = -1
+					}
+				}
+				order = append(order, pos)
+			}
+			for f, field := range fields {
+				v := ratingValue(item.Ratings[field.Field])
+				if v == "" {
+					continue
+				}
+				code := scales[f].index(v)
+				if code < 0 {
+					return nil, fmt.Errorf("%s: item %d %s: %q is not on the scale", sheet.Rater, item.Item, field.Field, v)
+				}
+				codes[pos][f][r] = code
+			}
+		}
+	}
+	sort.Ints(order)
+	report.Items = len(order)
+
+	for f, field := range fields {
+		var m ratingMatrix
+		for _, pos := range order {
+			m = append(m, codes[pos][f])
+		}
+		fa := FieldAgreement{Field: field.Field, Ordinal: field.Ordinal, Units: len(m.pairable())}
+		fa.PercentAgreement = finite(percentAgreement(m))
+		if len(sheets) == 2 {
+			fa.CohensKappa = finite(cohensKappa(m, scales[f]))
+		} else {
+			fa.FleissKappa = finite(fleissKappa(m, scales[f]))
+		}
+		fa.Alpha = finite(krippendorffAlpha(m, scales[f]))
+		if k := fa.Kappa(); k != nil && *k >= report.Threshold {
+			fa.MeetsThreshold = true
+		}
+		report.Fields = append(report.Fields, fa)
+	}
+
+	for _, pos := range order {
+		d := Disagreement{Activity: ds.activities[pos]}
+		for f, field := range fields {
+			vals := codes[pos][f]
+			score := unitDisagreement(vals, scales[f])
+			if score == 0 {
+				continue
+			}
+			d.Score += score
+			fd := FieldDisagreement{Field: field.Field}
+			for _, v := range vals {
+				if v < 0 {
+					fd.Values = append(fd.Values, "")
+				} else {
+					fd.Values = append(fd.Values, scales[f].values[v])
+				}
+			}
+			d.Fields = append(d.Fields, fd)
+		}
+		if d.Score > 0 {
+			report.Disagreements = append(report.Disagreements, d)
+		}
+	}
+	sort.SliceStable(report.Disagreements, func(i, j int) bool {
+		return report.Disagreements[i].Score > report.Disagreements[j].Score
+	})
+	return report, nil
+}
+
+// ratingValue normalises a rating to its string form, "" if unrated
+func ratingValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(x)
+	}
+	return fmt.Sprint(v)
+}
+
+// finite returns a pointer to x, or nil if x is NaN
+func finite(x float64) *float64 {
+	if math.IsNaN(x) {
+		return nil
+	}
+	return &x
+}
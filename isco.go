@@ -0,0 +1,189 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OccupationProfile is the automation exposure of an ISCO-08 major group,
+// built from the HAAI categories and domains its tasks map to. Each
+// component is summarised on its own and the profile averages the
+// components by weight, so a large domain does not swamp a small category.
+type OccupationProfile struct {
+	Group        int                `json:"iscoGroup"`
+	Name         string             `json:"iscoName"`
+	SampleTasks  []string           `json:"sampleTasks"`
+	Activities   int                `json:"activities"` // distinct activities across components
+	Readiness    float64            `json:"automationReadiness"`
+	Essentiality float64            `json:"humanEssentiality"`
+	MeanWave     *float64           `json:"meanWave"`
+	Capability   map[string]float64 `json:"capability"` // weighted share of activities per aiCapability level
+	Waves        map[int]float64    `json:"waves"`      // weighted share of activities per agiWave
+	Components   []ProfileComponent `json:"components"`
+}
+
+// ProfileComponent is one category or domain of an occupation profile.
+// Weights are normalised to sum to 1 across the profile.
+type ProfileComponent struct {
+	Ref    HAAIRef `json:"ref"`
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+	ActivityRollup
+}
+
+// ISCOWeights holds user task weights per ISCO group: for each group, the
+// weight of each HAAI category or domain ID. A group with weights is
+// profiled from exactly those references instead of iscoMapping's, which
+// are weighted equally.
+type ISCOWeights map[int]map[HAAIRef]float64
+
+// ReadISCOWeights reads task weights from a JSON file of the form
+// {"4": {"1.2": 0.5, "2.3": 0.2, "5.1": 0.3}}.
+func ReadISCOWeights(path string) (ISCOWeights, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]map[string]float64
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	w := make(ISCOWeights)
+	for group, refs := range raw {
+		g, err := strconv.Atoi(strings.TrimSpace(group))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %q is not an ISCO group number", path, group)
+		}
+		w[g] = make(map[HAAIRef]float64)
+		for ref, weight := range refs {
+			w[g][HAAIRef(strings.TrimSpace(ref))] = weight
+		}
+	}
+	return w, nil
+}
+
+// ParseRefWeights parses inline weights such as "3.5=0.5,4.3=0.3,5.1=0.2".
+func ParseRefWeights(s string) (map[HAAIRef]float64, error) {
+	w := make(map[HAAIRef]float64)
+	for _, part := range strings.Split(s, ",") {
+		ref, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid weight %q (want id=weight)", part)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight %q (want id=weight)", part)
+		}
+		w[HAAIRef(strings.TrimSpace(ref))] = weight
+	}
+	return w, nil
+}
+
+// OccupationProfiles profiles every ISCO major group in iscoMapping, most
+// exposed (highest automationReadiness) first. weights may be nil.
+func (ds *Dataset) OccupationProfiles(weights ISCOWeights) ([]OccupationProfile, error) {
+	var out []OccupationProfile
+	for _, e := range ds.mappings.ISCOMapping.Mappings {
+		p, err := ds.occupationProfile(e, weights[e.ISCOGroup])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *p)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Readiness > out[j].Readiness })
+	return out, nil
+}
+
+// OccupationProfile profiles one ISCO major group. weights may be nil.
+func (ds *Dataset) OccupationProfile(group int, weights map[HAAIRef]float64) (*OccupationProfile, error) {
+	for _, e := range ds.mappings.ISCOMapping.Mappings {
+		if e.ISCOGroup == group {
+			return ds.occupationProfile(e, weights)
+		}
+	}
+	return nil, fmt.Errorf("ISCO group %d is not in iscoMapping", group)
+}
+
+func (ds *Dataset) occupationProfile(e ISCOEntry, weights map[HAAIRef]float64) (*OccupationProfile, error) {
+	p := &OccupationProfile{
+		Group:       e.ISCOGroup,
+		Name:        e.ISCOName,
+		SampleTasks: e.SampleTasks,
+		Capability:  make(map[string]float64),
+		Waves:       make(map[int]float64),
+	}
+
+	refs := e.HAAICategories
+	if len(weights) > 0 {
+		refs = nil
+		for ref, w := range weights {
+			if w < 0 {
+				return nil, fmt.Errorf("ISCO group %d: weight for %s is negative", e.ISCOGroup, ref)
+			}
+			if w > 0 {
+				refs = append(refs, ref)
+			}
+		}
+		sort.Slice(refs, func(i, j int) bool { return CompareIDs(string(refs[i]), string(refs[j])) < 0 })
+	}
+
+	var total float64
+	for _, ref := range refs {
+		w := 1.0
+		if len(weights) > 0 {
+			w = weights[ref]
+		}
+		acts, err := ds.ResolveRef(ref)
+		if err != nil {
+			return nil, fmt.Errorf("ISCO group %d: %w", e.ISCOGroup, err)
+		}
+		p.Components = append(p.Components, ProfileComponent{Ref: ref, Name: ds.RefName(ref), Weight: w, ActivityRollup: Rollup(acts)})
+		total += w
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("ISCO group %d has no weighted categories", e.ISCOGroup)
+	}
+
+	all, _ := ds.ResolveRefs(refs)
+	p.Activities = len(all)
+	var scored, waveWeight, waveSum float64
+	for i := range p.Components {
+		c := &p.Components[i]
+		c.Weight /= total
+		if c.Activities == 0 {
+			continue
+		}
+		scored += c.Weight
+		p.Readiness += c.Weight * c.Readiness
+		p.Essentiality += c.Weight * c.Essentiality
+		for level, n := range c.Capability {
+			p.Capability[level] += c.Weight * float64(n) / float64(c.Activities)
+		}
+		for wave, n := range c.Waves {
+			p.Waves[wave] += c.Weight * float64(n) / float64(c.Activities)
+		}
+		if c.MeanWave != nil {
+			waveWeight += c.Weight
+			waveSum += c.Weight * *c.MeanWave
+		}
+	}
+	if scored > 0 {
+		p.Readiness /= scored
+		p.Essentiality /= scored
+		for level := range p.Capability {
+			p.Capability[level] /= scored
+		}
+		for wave := range p.Waves {
+			p.Waves[wave] /= scored
+		}
+	}
+	if waveWeight > 0 {
+		mean := waveSum / waveWeight
+		p.MeanWave = &mean
+	}
+	return p, nil
+}
@@ -0,0 +1,273 @@
+package haai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// orderedObject is a JSON object that remembers key order and whether it was
+// written on a single line, so data files can be edited and written back with
+// reviewable diffs. Values are *orderedObject, *orderedArray, json.Number,
+// string, bool or nil.
+type orderedObject struct {
+	keys   []string
+	values map[string]any
+	inline bool
+	tight  bool // inline without spaces inside the braces
+}
+
+// orderedArray is a JSON array that remembers whether it was written on a single line.
+type orderedArray struct {
+	items  []any
+	inline bool
+}
+
+func newOrderedObject() *orderedObject {
+	return &orderedObject{values: make(map[string]any)}
+}
+
+func (o *orderedObject) Get(key string) (any, bool) {
+	v, ok := o.values[key]
+	return v, ok
+}
+
+// Set replaces the value of key, appending the key if it is new.
+func (o *orderedObject) Set(key string, v any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = v
+}
+
+// Object returns the nested object at key, or nil.
+func (o *orderedObject) Object(key string) *orderedObject {
+	child, _ := o.values[key].(*orderedObject)
+	return child
+}
+
+// parseOrdered decodes a JSON document into ordered values
+func parseOrdered(data []byte) (*orderedObject, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeOrdered(dec, data)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := v.(*orderedObject)
+	if !ok {
+		return nil, fmt.Errorf("top-level JSON value is not an object")
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after top-level object")
+	}
+	return obj, nil
+}
+
+func decodeOrdered(dec *json.Decoder, data []byte) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	start := dec.InputOffset()
+	singleLine := func() bool {
+		return !bytes.Contains(data[start:dec.InputOffset()], []byte("\n"))
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := newOrderedObject()
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := keyTok.(string)
+				val, err := decodeOrdered(dec, data)
+				if err != nil {
+					return nil, err
+				}
+				obj.Set(key, val)
+			}
+			_, err := dec.Token() // closing '}'
+			obj.inline = singleLine()
+			obj.tight = obj.inline && start < int64(len(data)) && data[start] != ' '
+			return obj, err
+		case '[':
+			arr := &orderedArray{items: []any{}}
+			for dec.More() {
+				val, err := decodeOrdered(dec, data)
+				if err != nil {
+					return nil, err
+				}
+				arr.items = append(arr.items, val)
+			}
+			_, err := dec.Token() // closing ']'
+			arr.inline = singleLine()
+			return arr, err
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", t)
+	default:
+		return t, nil
+	}
+}
+
+// expand marks v and everything below it as multiline
+func expand(v any) {
+	switch x := v.(type) {
+	case *orderedObject:
+		x.inline = false
+		for _, child := range x.values {
+			expand(child)
+		}
+	case *orderedArray:
+		x.inline = false
+		for _, child := range x.items {
+			expand(child)
+		}
+	}
+}
+
+// encodeOrdered renders v with two-space indentation and a trailing newline
+func encodeOrdered(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeOrdered(&buf, v, 0); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func writeOrdered(buf *bytes.Buffer, v any, depth int) error {
+	indent := strings.Repeat("  ", depth+1)
+	closing := strings.Repeat("  ", depth)
+	switch x := v.(type) {
+	case *orderedObject:
+		if len(x.keys) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		if x.inline {
+			return writeInline(buf, x)
+		}
+		buf.WriteString("{\n")
+		for i, k := range x.keys {
+			buf.WriteString(indent)
+			writeScalar(buf, k)
+			buf.WriteString(": ")
+			if err := writeOrdered(buf, x.values[k], depth+1); err != nil {
+				return err
+			}
+			if i < len(x.keys)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(closing + "}")
+	case *orderedArray:
+		if len(x.items) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		if x.inline {
+			return writeInline(buf, x)
+		}
+		buf.WriteString("[\n")
+		for i, item := range x.items {
+			buf.WriteString(indent)
+			if err := writeOrdered(buf, item, depth+1); err != nil {
+				return err
+			}
+			if i < len(x.items)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(closing + "]")
+	default:
+		return writeScalar(buf, x)
+	}
+	return nil
+}
+
+// writeInline renders a value on one line
+func writeInline(buf *bytes.Buffer, v any) error {
+	switch x := v.(type) {
+	case *orderedObject:
+		open, close := "{ ", " }"
+		if x.tight || len(x.keys) == 0 {
+			open, close = "{", "}"
+		}
+		buf.WriteString(open)
+		for i, k := range x.keys {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeScalar(buf, k)
+			buf.WriteString(": ")
+			if err := writeInline(buf, x.values[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(close)
+	case *orderedArray:
+		buf.WriteByte('[')
+		for i, item := range x.items {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if err := writeInline(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		return writeScalar(buf, x)
+	}
+	return nil
+}
+
+func writeScalar(buf *bytes.Buffer, v any) error {
+	if n, ok := v.(json.Number); ok {
+		buf.WriteString(n.String())
+		return nil
+	}
+	var tmp bytes.Buffer
+	enc := json.NewEncoder(&tmp)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimRight(tmp.Bytes(), "\n"))
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory and
+// renames it over path, so readers never see a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,303 @@
+package haai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LintIssue is a single inconsistency found between the data files.
+type LintIssue struct {
+	File    string `json:"file"`
+	ID      string `json:"id,omitempty"`
+	Message string `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	if i.ID == "" {
+		return fmt.Sprintf("%s: %s", i.File, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.File, i.ID, i.Message)
+}
+
+// Lint cross-checks activities.json, activities/domain-N.json, indices/*.json,
+// the latest assessment, mappings.json and taxonomy.json against each other. Unlike Load it
+// does not skip missing or malformed files; every problem becomes an issue.
+func Lint(dir string) ([]LintIssue, error) {
+	l := &linter{ds: &Dataset{dir: dir}}
+
+	var t Taxonomy
+	if err := l.ds.loadJSON("taxonomy.json", &t); err != nil {
+		return nil, err
+	}
+	l.categories = make(map[string]bool)
+	l.domains = make(map[int]bool)
+	for _, d := range t.Domains {
+		l.domains[d.ID] = true
+		for _, c := range d.Categories {
+			l.categories[c.ID] = true
+		}
+	}
+
+	l.lintActivitiesFile()
+	l.lintDomainFiles(t.Domains)
+	l.lintIndices()
+	l.lintAssessment()
+	l.lintMappings()
+
+	return l.issues, nil
+}
+
+type linter struct {
+	ds         *Dataset
+	categories map[string]bool
+	domains    map[int]bool
+	activities map[string]Activity
+	order      []string
+	issues     []LintIssue
+}
+
+func (l *linter) add(file, id, format string, args ...any) {
+	l.issues = append(l.issues, LintIssue{File: file, ID: id, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) lintActivitiesFile() {
+	const file = "activities.json"
+	l.activities = make(map[string]Activity)
+
+	var af ActivitiesFile
+	if err := l.ds.loadJSON(file, &af); err != nil {
+		l.add(file, "", "%v", err)
+		return
+	}
+	if af.ActivitiesCount != len(af.Activities) {
+		l.add(file, "", "activitiesCount is %d but %d activities are listed", af.ActivitiesCount, len(af.Activities))
+	}
+
+	for _, a := range af.Activities {
+		if _, dup := l.activities[a.ID]; dup {
+			l.add(file, a.ID, "duplicate activity ID")
+			continue
+		}
+		l.activities[a.ID] = a
+		l.order = append(l.order, a.ID)
+
+		if !l.categories[a.CategoryID] {
+			l.add(file, a.ID, "categoryId %q not found in taxonomy.json", a.CategoryID)
+		}
+		if !strings.HasPrefix(a.ID, a.CategoryID+".") {
+			l.add(file, a.ID, "ID does not start with categoryId %q", a.CategoryID)
+		}
+		if d := DomainFromID(a.ID); d != a.DomainID {
+			l.add(file, a.ID, "domainId is %d but ID prefix is domain %d", a.DomainID, d)
+		}
+	}
+}
+
+func (l *linter) lintDomainFiles(domains []Domain) {
+	seen := make(map[string]bool)
+	for _, d := range domains {
+		file := fmt.Sprintf("activities/domain-%d.json", d.ID)
+		var df DomainFile
+		if err := l.ds.loadJSON(file, &df); err != nil {
+			l.add(file, "", "%v", err)
+			continue
+		}
+		if df.DomainID != d.ID {
+			l.add(file, "", "domainId is %d, expected %d", df.DomainID, d.ID)
+		}
+		for _, a := range df.Activities {
+			seen[a.ID] = true
+			if _, ok := l.activities[a.ID]; !ok {
+				l.add(file, a.ID, "orphan activity not listed in activities.json")
+			}
+			if p := DomainFromID(a.ID); p != d.ID {
+				l.add(file, a.ID, "ID prefix is domain %d but file is domain %d", p, d.ID)
+			}
+			if !l.categories[a.CategoryID] {
+				l.add(file, a.ID, "categoryId %q not found in taxonomy.json", a.CategoryID)
+			}
+		}
+	}
+	for _, id := range l.order {
+		if !seen[id] {
+			l.add("activities.json", id, "activity missing from activities/domain-%d.json", DomainFromID(id))
+		}
+	}
+}
+
+func (l *linter) lintIndices() {
+	files, err := filepath.Glob(filepath.Join(l.ds.dir, "indices", "*.json"))
+	if err != nil || len(files) == 0 {
+		l.add("indices/", "", "no index files found")
+		return
+	}
+	for _, path := range files {
+		file := "indices/" + filepath.Base(path)
+		var idx IndexFile
+		if err := l.ds.loadJSON(file, &idx); err != nil {
+			l.add(file, "", "%v", err)
+			continue
+		}
+		if idx.Scale.Min > idx.Scale.Max {
+			l.add(file, "", "scale.min %d is greater than scale.max %d", idx.Scale.Min, idx.Scale.Max)
+		}
+		for _, id := range sortedKeys(idx.Values) {
+			v := idx.Values[id]
+			if _, ok := l.activities[id]; !ok {
+				l.add(file, id, "orphan value for unknown activity")
+			}
+			if v < idx.Scale.Min || v > idx.Scale.Max {
+				l.add(file, id, "value %d outside scale %d-%d", v, idx.Scale.Min, idx.Scale.Max)
+			}
+		}
+		for _, id := range l.order {
+			if _, ok := idx.Values[id]; !ok {
+				l.add(file, id, "missing score")
+			}
+		}
+	}
+}
+
+func (l *linter) lintAssessment() {
+	name, err := l.ds.latestAssessmentFile("")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			l.add("assessments/", "", "directory not found")
+		} else {
+			l.add("assessments/", "", "%v", err)
+		}
+		return
+	}
+	file := "assessments/" + name
+	var af AssessmentFile
+	if err := l.ds.loadJSON(file, &af); err != nil {
+		l.add(file, "", "%v", err)
+		return
+	}
+	windows := af.WaveWindows()
+	for _, id := range sortedKeys(af.ActivityAssessments) {
+		if id == "description" {
+			continue
+		}
+		var aa ActivityAssessment
+		if err := json.Unmarshal(af.ActivityAssessments[id], &aa); err != nil {
+			l.add(file, id, "malformed assessment: %v", err)
+			continue
+		}
+		if _, ok := l.activities[id]; !ok {
+			l.add(file, id, "orphan assessment for unknown activity")
+		}
+		if aa.AICapability == "" || aa.Bottleneck == "" || aa.AGIWave == 0 {
+			l.add(file, id, "incomplete assessment (aiCapability, bottleneck and agiWave are required)")
+		}
+		if aa.Forecast != nil {
+			if err := aa.Forecast.Validate(); err != nil {
+				l.add(file, id, "%v", err)
+			}
+			var waves []int
+			for w := range aa.Forecast.WaveProbabilities {
+				waves = append(waves, w)
+			}
+			sort.Ints(waves)
+			for _, w := range waves {
+				if _, ok := windows[w]; !ok {
+					l.add(file, id, "forecast wave %d has no agiWaveTimelines window", w)
+				}
+			}
+		}
+	}
+	for _, id := range l.order {
+		if _, ok := af.ActivityAssessments[id]; !ok {
+			l.add(file, id, "missing assessment")
+		}
+	}
+}
+
+// lintMappings checks that every crosswalk and aiCapabilityAlignment
+// reference in mappings.json names an existing domain or category, and that ATUS breakdownRefs and
+// haaiWeights stay within their entry's haaiCategories
+func (l *linter) lintMappings() {
+	const file = "mappings.json"
+	var m Mappings
+	if err := l.ds.loadJSON(file, &m); err != nil {
+		l.add(file, "", "%v", err)
+		return
+	}
+	for _, e := range m.crosswalkEntries() {
+		l.lintRefs(file, e.System+" "+e.Code, e.Refs)
+	}
+	for _, sys := range m.AICapabilityAlignment.Systems {
+		id := "aiCapabilityAlignment " + sys.System
+		if len(sys.Refs()) == 0 {
+			l.add(file, id, "no haaiDomains or haaiCategories")
+		}
+		l.lintRefs(file, id, sys.Refs())
+		if len(sys.PredictedWave) == 0 {
+			l.add(file, id, "no predictedWave")
+		}
+	}
+
+	// ATUS allocation refs must be among the entry's own haaiCategories
+	for _, e := range m.ATUSMapping.Mappings {
+		id := SystemATUS + " " + e.ATUSCode
+		for _, item := range sortedKeys(e.BreakdownRefs) {
+			if _, ok := e.Breakdown[item]; !ok {
+				l.add(file, id, "breakdownRefs item %q is not in breakdown", item)
+			}
+			for _, r := range e.BreakdownRefs[item] {
+				if !containsString(e.HAICategories, string(r)) {
+					l.add(file, id, "breakdownRefs item %q: %s is not in haaiCategories", item, r)
+				}
+			}
+		}
+		weights := make(map[string]float64)
+		for r, w := range e.HAAIWeights {
+			weights[string(r)] = w
+		}
+		for _, r := range sortedKeys(weights) {
+			w := weights[r]
+			if !containsString(e.HAICategories, r) {
+				l.add(file, id, "haaiWeights: %s is not in haaiCategories", r)
+			}
+			if w < 0 {
+				l.add(file, id, "haaiWeights: weight for %s is negative", r)
+			}
+		}
+	}
+}
+
+// lintRefs checks that references name existing domains and categories
+func (l *linter) lintRefs(file, id string, refs []HAAIRef) {
+	for _, r := range refs {
+		if !r.IsDomain() {
+			if !l.categories[string(r)] {
+				l.add(file, id, "category %q not found in taxonomy.json", r)
+			}
+			continue
+		}
+		first, last, ok := r.Domains()
+		if !ok {
+			l.add(file, id, "invalid HAAI reference %q", r)
+			continue
+		}
+		for d := first; d <= last; d++ {
+			if !l.domains[d] {
+				l.add(file, id, "domain %d not found in taxonomy.json", d)
+			}
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
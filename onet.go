@@ -0,0 +1,277 @@
+package haai
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ONETActivityExposure rolls the HAAI activities mapped to one O*NET
+// Generalized Work Activity up into a single exposure summary.
+type ONETActivityExposure struct {
+	Category string    `json:"onetCategory"`
+	Activity string    `json:"onetActivity"`
+	Refs     []HAAIRef `json:"haaiRefs"`
+	ActivityRollup
+}
+
+// ONETCrosswalk resolves mappings.json's onetMapping and returns one rollup
+// per O*NET work activity, in file order.
+func (ds *Dataset) ONETCrosswalk() ([]ONETActivityExposure, error) {
+	var out []ONETActivityExposure
+	for _, e := range ds.mappings.ONETMapping.Mappings {
+		acts, err := ds.ResolveRefs(e.HAAIDomains)
+		if err != nil {
+			return nil, fmt.Errorf("mappings.json: onetMapping %q: %w", e.ONETCategory, err)
+		}
+		rollup := Rollup(acts)
+		for _, name := range e.ONETActivities {
+			out = append(out, ONETActivityExposure{
+				Category:       e.ONETCategory,
+				Activity:       name,
+				Refs:           e.HAAIDomains,
+				ActivityRollup: rollup,
+			})
+		}
+	}
+	return out, nil
+}
+
+// OccupationExposure is the automation exposure of one O*NET occupation:
+// the HAAI scores of its work activities or tasks, averaged with the
+// activities' importance as weights.
+type OccupationExposure struct {
+	Code         string   `json:"code"` // O*NET-SOC code, e.g. "15-1252.00"
+	Title        string   `json:"title,omitempty"`
+	Readiness    float64  `json:"automationReadiness"`
+	Essentiality float64  `json:"humanEssentiality"`
+	MeanWave     *float64 `json:"meanWave"`
+	Coverage     float64  `json:"coverage"` // share of the weight that maps to HAAI
+	Items        int      `json:"items"`    // work activities or tasks read
+
+	weight, mapped, waveWeight, waveSum float64
+}
+
+// add accumulates one work activity or task of the given weight
+func (o *OccupationExposure) add(weight float64, r *ActivityRollup) {
+	o.Items++
+	o.weight += weight
+	if r == nil || r.Activities == 0 {
+		return
+	}
+	o.mapped += weight
+	o.Readiness += weight * r.Readiness
+	o.Essentiality += weight * r.Essentiality
+	if r.MeanWave != nil {
+		o.waveWeight += weight
+		o.waveSum += weight * *r.MeanWave
+	}
+}
+
+func (o *OccupationExposure) finish() {
+	if o.mapped > 0 {
+		o.Readiness /= o.mapped
+		o.Essentiality /= o.mapped
+	}
+	if o.weight > 0 {
+		o.Coverage = o.mapped / o.weight
+	}
+	if o.waveWeight > 0 {
+		mean := o.waveSum / o.waveWeight
+		o.MeanWave = &mean
+	}
+}
+
+// ONET import kinds
+const (
+	ONETWorkActivities = "workActivities"
+	ONETTasks          = "tasks"
+)
+
+// ONETImport is the per-occupation exposure computed from an O*NET file.
+type ONETImport struct {
+	File        string               `json:"file"`
+	Kind        string               `json:"kind"`
+	Occupations []OccupationExposure `json:"occupations"` // most exposed first
+
+	// Unmatched lists work activities in the file that have no HAAI mapping
+	Unmatched []string `json:"unmatched,omitempty"`
+}
+
+// ImportONET computes per-occupation automation exposure from a local O*NET
+// database file, tab-separated (the O*NET text release) or comma-separated,
+// with an "O*NET-SOC Code" column and, if present, a "Title" column:
+//
+//   - Work Activities: rows with "Element Name", "Scale ID" and "Data Value".
+//     Each Generalized Work Activity is scored through onetMapping and
+//     weighted by its importance (scale IM).
+//   - Task Statements: rows with "Task". Each task is classified into its
+//     best matching HAAI category and weighted equally.
+func (ds *Dataset) ImportONET(path string) (*ONETImport, error) {
+	header, rows, err := readTable(path)
+	if err != nil {
+		return nil, err
+	}
+	col := func(names ...string) int {
+		for i, h := range header {
+			h = strings.ToLower(strings.TrimSpace(h))
+			for _, n := range names {
+				if h == n {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+	codeCol := col("o*net-soc code", "onetsoc_code", "code")
+	titleCol := col("title")
+	elementCol := col("element name", "element_name")
+	taskCol := col("task")
+	if codeCol < 0 {
+		return nil, fmt.Errorf("%s: no O*NET-SOC Code column", path)
+	}
+
+	imp := &ONETImport{File: filepath.Base(path)}
+	byCode := make(map[string]*OccupationExposure)
+	var order []string
+	occupation := func(row []string) *OccupationExposure {
+		code := field(row, codeCol)
+		o, ok := byCode[code]
+		if !ok {
+			o = &OccupationExposure{Code: code, Title: field(row, titleCol)}
+			byCode[code] = o
+			order = append(order, code)
+		}
+		return o
+	}
+
+	switch {
+	case elementCol >= 0:
+		imp.Kind = ONETWorkActivities
+		xwalk, err := ds.ONETCrosswalk()
+		if err != nil {
+			return nil, err
+		}
+		scaleCol := col("scale id", "scale_id")
+		valueCol := col("data value", "data_value")
+		unmatched := make(map[string]bool)
+		for n, row := range rows {
+			if scaleCol >= 0 && !strings.EqualFold(field(row, scaleCol), "IM") {
+				continue
+			}
+			weight := 1.0
+			if valueCol >= 0 {
+				weight, err = strconv.ParseFloat(field(row, valueCol), 64)
+				if err != nil {
+					return nil, fmt.Errorf("%s: line %d: invalid data value %q", path, n+2, field(row, valueCol))
+				}
+			}
+			name := field(row, elementCol)
+			x := onetActivity(xwalk, name)
+			if x == nil {
+				unmatched[name] = true
+				occupation(row).add(weight, nil)
+				continue
+			}
+			occupation(row).add(weight, &x.ActivityRollup)
+		}
+		imp.Unmatched = sortedKeys(unmatched)
+	case taskCol >= 0:
+		imp.Kind = ONETTasks
+		rollups := make(map[string]*ActivityRollup)
+		for _, row := range rows {
+			var r *ActivityRollup
+			if c := ds.Classify(field(row, taskCol), 1); len(c.Candidates) > 0 {
+				id := c.Candidates[0].Category.ID
+				if rollups[id] == nil {
+					acts, _ := ds.ResolveRef(HAAIRef(id))
+					rollup := Rollup(acts)
+					rollups[id] = &rollup
+				}
+				r = rollups[id]
+			}
+			occupation(row).add(1, r)
+		}
+	default:
+		return nil, fmt.Errorf("%s: not an O*NET Work Activities or Task Statements file (no Element Name or Task column)", path)
+	}
+
+	for _, code := range order {
+		o := byCode[code]
+		o.finish()
+		imp.Occupations = append(imp.Occupations, *o)
+	}
+	sort.SliceStable(imp.Occupations, func(i, j int) bool {
+		a, b := imp.Occupations[i], imp.Occupations[j]
+		if a.Readiness != b.Readiness {
+			return a.Readiness > b.Readiness
+		}
+		return a.Code < b.Code
+	})
+	return imp, nil
+}
+
+// onetActivity finds the crosswalk entry for an O*NET element name. Mapped
+// names may be shortened, so an element matches a mapped name it starts with.
+func onetActivity(xwalk []ONETActivityExposure, element string) *ONETActivityExposure {
+	element = normalizeName(element)
+	for i := range xwalk {
+		name := normalizeName(xwalk[i].Activity)
+		if element == name {
+			return &xwalk[i]
+		}
+		if rest, ok := strings.CutPrefix(element, name); ok && (rest[0] == ' ' || rest[0] == ',') {
+			return &xwalk[i]
+		}
+	}
+	return nil
+}
+
+// normalizeName lowercases s and collapses runs of whitespace
+func normalizeName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// readTable reads a tab- or comma-separated file with a header row. Tab
+// files are split verbatim, since O*NET text files do not quote fields.
+func readTable(path string) ([]string, [][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	first, _, _ := strings.Cut(text, "\n")
+
+	var records [][]string
+	if strings.Contains(first, "\t") {
+		for _, line := range strings.Split(text, "\n") {
+			line = strings.TrimRight(line, "\r")
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			records = append(records, strings.Split(line, "\t"))
+		}
+	} else {
+		r := csv.NewReader(strings.NewReader(text))
+		r.FieldsPerRecord = -1
+		if records, err = r.ReadAll(); err != nil {
+			return nil, nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("%s is empty", path)
+	}
+	return records[0], records[1:], nil
+}
+
+// field returns row[i] trimmed, or "" if the column is absent
+func field(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
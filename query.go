@@ -0,0 +1,389 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Query selects, orders and limits activities. Where uses the expression
+// syntax accepted by ParseQuery; an empty Where matches every activity.
+type Query struct {
+	Where string
+	Sort  []SortKey
+	Limit int
+}
+
+// SortKey orders query results by one field.
+type SortKey struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// Query runs q against the dataset's activities. Results keep dataset order
+// unless q.Sort is set; ties are broken by activity ID.
+func (ds *Dataset) Query(q Query) ([]Activity, error) {
+	var where *Condition
+	if strings.TrimSpace(q.Where) != "" {
+		c, err := ds.ParseQuery(q.Where)
+		if err != nil {
+			return nil, err
+		}
+		where = c
+	}
+	for _, k := range q.Sort {
+		if !ds.HasField(k.Field) {
+			return nil, fmt.Errorf("unknown sort field %q", k.Field)
+		}
+	}
+
+	var out []Activity
+	for i := range ds.activities {
+		if where == nil || where.Match(&ds.activities[i]) {
+			out = append(out, ds.activities[i])
+		}
+	}
+	if len(q.Sort) > 0 {
+		sort.SliceStable(out, func(i, j int) bool {
+			for _, k := range q.Sort {
+				c := compareFieldValues(&out[i], &out[j], k.Field)
+				if c != 0 {
+					return (c < 0) != k.Desc
+				}
+			}
+			return CompareIDs(out[i].ID, out[j].ID) < 0
+		})
+	}
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out, nil
+}
+
+// ParseSort parses a sort specification such as "errorTolerance desc, id".
+func ParseSort(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(spec, ",") {
+		words := strings.Fields(part)
+		if len(words) == 0 {
+			continue
+		}
+		key := SortKey{Field: words[0]}
+		if len(words) > 2 {
+			return nil, fmt.Errorf("invalid sort key %q", strings.TrimSpace(part))
+		}
+		if len(words) == 2 {
+			switch strings.ToLower(words[1]) {
+			case "asc":
+			case "desc":
+				key.Desc = true
+			default:
+				return nil, fmt.Errorf("invalid sort direction %q (use asc or desc)", words[1])
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// compareFieldValues orders two activities by a field: numbers numerically,
+// activity IDs by their dotted parts and other strings case-insensitively
+func compareFieldValues(a, b *Activity, field string) int {
+	if canonicalField(field) == "id" {
+		return CompareIDs(a.ID, b.ID)
+	}
+	va, _ := a.Field(field)
+	vb, _ := b.Field(field)
+	switch x := va.(type) {
+	case float64:
+		y, _ := vb.(float64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	case string:
+		y, _ := vb.(string)
+		return strings.Compare(strings.ToLower(x), strings.ToLower(y))
+	}
+	return 0
+}
+
+// ParseQuery compiles a filter expression into a Condition, so expressions
+// and the JSON rules in scoring.json share one evaluator. For example:
+//
+//	domain in (4,5) and abstraction<=2 and capability!=solved and wave>=3
+//
+// Comparisons are field op value with op one of =, ==, !=, <, <=, >, >=,
+// "in (a, b)", "not in (a, b)" and "between x and y". They combine with
+// "and", "or" and parentheses; "and" binds tighter. Field names are those
+// accepted by Activity.Field. String values may be bare words or quoted.
+func (ds *Dataset) ParseQuery(expr string) (*Condition, error) {
+	p := &queryParser{ds: ds}
+	if err := p.lex(expr); err != nil {
+		return nil, err
+	}
+	c, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("query: unexpected %q at position %d", t.text, t.pos+1)
+	}
+	if err := ds.ValidateCondition(c); err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	return c, nil
+}
+
+const (
+	tokEOF = iota
+	tokWord
+	tokString
+	tokOp
+	tokOpen
+	tokClose
+	tokComma
+)
+
+type queryToken struct {
+	kind int
+	text string
+	pos  int
+}
+
+type queryParser struct {
+	ds     *Dataset
+	tokens []queryToken
+	next   int
+}
+
+func (p *queryParser) lex(s string) error {
+	runes := []rune(s)
+	isWord := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-+", r)
+	}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == '[':
+			p.tokens = append(p.tokens, queryToken{tokOpen, string(r), i})
+			i++
+		case r == ')' || r == ']':
+			p.tokens = append(p.tokens, queryToken{tokClose, string(r), i})
+			i++
+		case r == ',':
+			p.tokens = append(p.tokens, queryToken{tokComma, ",", i})
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return fmt.Errorf("query: unterminated string at position %d", i+1)
+			}
+			p.tokens = append(p.tokens, queryToken{tokString, string(runes[i+1 : end]), i})
+			i = end + 1
+		case strings.ContainsRune("=!<>", r):
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return fmt.Errorf("query: unexpected '!' at position %d", i+1)
+			}
+			p.tokens = append(p.tokens, queryToken{tokOp, op, i})
+			i += len(op)
+		case isWord(r):
+			end := i
+			for end < len(runes) && isWord(runes[end]) {
+				end++
+			}
+			p.tokens = append(p.tokens, queryToken{tokWord, string(runes[i:end]), i})
+			i = end
+		default:
+			return fmt.Errorf("query: unexpected %q at position %d", r, i+1)
+		}
+	}
+	p.tokens = append(p.tokens, queryToken{kind: tokEOF, text: "end of query", pos: len(runes)})
+	return nil
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.next]
+}
+
+func (p *queryParser) take() queryToken {
+	t := p.tokens[p.next]
+	if t.kind != tokEOF {
+		p.next++
+	}
+	return t
+}
+
+// keyword reports whether the next token is the given keyword and consumes it
+func (p *queryParser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == tokWord && strings.EqualFold(t.text, kw) {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) parseOr() (*Condition, error) {
+	var terms []Condition
+	for {
+		c, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, *c)
+		if !p.keyword("or") {
+			break
+		}
+	}
+	if len(terms) == 1 {
+		return &terms[0], nil
+	}
+	return &Condition{Any: terms}, nil
+}
+
+func (p *queryParser) parseAnd() (*Condition, error) {
+	var terms []Condition
+	for {
+		c, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, *c)
+		if !p.keyword("and") {
+			break
+		}
+	}
+	if len(terms) == 1 {
+		return &terms[0], nil
+	}
+	return &Condition{All: terms}, nil
+}
+
+func (p *queryParser) parsePrimary() (*Condition, error) {
+	if p.peek().kind == tokOpen {
+		open := p.take()
+		c, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.take(); t.kind != tokClose {
+			return nil, fmt.Errorf("query: missing ')' for '(' at position %d", open.pos+1)
+		}
+		return c, nil
+	}
+
+	ft := p.take()
+	if ft.kind != tokWord {
+		return nil, fmt.Errorf("query: expected a field name at position %d, got %q", ft.pos+1, ft.text)
+	}
+	field := ft.text
+	if !p.ds.HasField(field) {
+		return nil, fmt.Errorf("query: unknown field %q at position %d", field, ft.pos+1)
+	}
+
+	var op string
+	var vals []any
+	var err error
+	switch t := p.peek(); {
+	case t.kind == tokOp:
+		p.take()
+		op = t.text
+		if op == "=" {
+			op = "=="
+		}
+		var v any
+		v, err = p.parseValue(field)
+		vals = []any{v}
+	case p.keyword("in"):
+		op = "in"
+		vals, err = p.parseList(field)
+	case p.keyword("not"):
+		if !p.keyword("in") {
+			return nil, fmt.Errorf("query: expected 'in' after 'not' at position %d", p.peek().pos+1)
+		}
+		op = "not_in"
+		vals, err = p.parseList(field)
+	case p.keyword("between"):
+		op = "between"
+		var lo, hi any
+		if lo, err = p.parseValue(field); err == nil {
+			if !p.keyword("and") {
+				return nil, fmt.Errorf("query: expected 'and' in between at position %d", p.peek().pos+1)
+			}
+			hi, err = p.parseValue(field)
+		}
+		vals = []any{lo, hi}
+	default:
+		return nil, fmt.Errorf("query: expected an operator after %q at position %d", field, t.pos+1)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []byte
+	if len(vals) == 1 && op != "in" && op != "not_in" {
+		raw, err = json.Marshal(vals[0])
+	} else {
+		raw, err = json.Marshal(vals)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Condition{Field: field, Op: op, Value: raw}, nil
+}
+
+func (p *queryParser) parseList(field string) ([]any, error) {
+	open := p.take()
+	if open.kind != tokOpen {
+		return nil, fmt.Errorf("query: expected '(' at position %d", open.pos+1)
+	}
+	var vals []any
+	for {
+		v, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, v)
+		t := p.take()
+		if t.kind == tokClose {
+			return vals, nil
+		}
+		if t.kind != tokComma {
+			return nil, fmt.Errorf("query: expected ',' or ')' at position %d", t.pos+1)
+		}
+	}
+}
+
+// parseValue reads a literal, typed by the field it is compared with: string
+// fields take the text as is, numeric fields require a number
+func (p *queryParser) parseValue(field string) (any, error) {
+	t := p.take()
+	if t.kind != tokWord && t.kind != tokString {
+		return nil, fmt.Errorf("query: expected a value at position %d, got %q", t.pos+1, t.text)
+	}
+	var zero Activity
+	if v, ok := zero.Field(field); ok {
+		if _, isString := v.(string); isString {
+			return t.text, nil
+		}
+	}
+	n, err := strconv.ParseFloat(t.text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("query: %s needs a number, got %q at position %d", field, t.text, t.pos+1)
+	}
+	return n, nil
+}
@@ -0,0 +1,269 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AssessmentDoc is an assessment snapshot opened for editing. Saving keeps
+// the file's key order and layout so git diffs only show real changes.
+type AssessmentDoc struct {
+	Date   string
+	Source string // snapshot NewAssessment cloned, relative to the dataset directory
+	ds     *Dataset
+	root   *orderedObject
+}
+
+// OpenAssessment opens the snapshot for the given date for editing.
+func (ds *Dataset) OpenAssessment(date string) (*AssessmentDoc, error) {
+	root, err := ds.readOrdered(assessmentPath(date))
+	if err != nil {
+		return nil, err
+	}
+	return &AssessmentDoc{Date: date, ds: ds, root: root}, nil
+}
+
+// NewAssessment scaffolds a snapshot for date by cloning the newest existing
+// one, carrying over agiWaveTimelines and compositeWeights, bumping the minor
+// version and appending a changeLog entry. The file is not written until Save.
+func (ds *Dataset) NewAssessment(date, message string) (*AssessmentDoc, error) {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", date)
+	}
+	dates, err := ds.AssessmentDates()
+	if err != nil {
+		return nil, err
+	}
+	if len(dates) == 0 {
+		return nil, errNoAssessments
+	}
+	latest := dates[len(dates)-1]
+	if date <= latest {
+		return nil, fmt.Errorf("new assessment date %s must be after the latest assessment (%s)", date, latest)
+	}
+
+	doc, err := ds.OpenAssessment(latest)
+	if err != nil {
+		return nil, err
+	}
+	doc.Date = date
+	doc.Source = assessmentPath(latest)
+	doc.root.Set("assessmentDate", date)
+
+	version := "1.0.0"
+	if v, ok := doc.root.Get("version"); ok {
+		version = fmt.Sprint(v)
+	}
+	version = bumpMinor(version)
+	doc.root.Set("version", version)
+
+	if message == "" {
+		message = "Scaffolded from " + doc.Source
+	}
+	doc.appendChangeLog(ChangeLogEntry{Date: date, Version: version, Changes: message})
+	return doc, nil
+}
+
+// Path returns the file path of the snapshot relative to the dataset directory.
+func (doc *AssessmentDoc) Path() string {
+	return assessmentPath(doc.Date)
+}
+
+// Exists reports whether the snapshot file is already on disk.
+func (doc *AssessmentDoc) Exists() bool {
+	_, err := os.Stat(filepath.Join(doc.ds.dir, doc.Path()))
+	return err == nil
+}
+
+// Missing returns the IDs of dataset activities that have no entry in the snapshot.
+func (doc *AssessmentDoc) Missing() []string {
+	entries := doc.entries()
+	var missing []string
+	for _, a := range doc.ds.activities {
+		if entries == nil || entries.Object(a.ID) == nil {
+			missing = append(missing, a.ID)
+		}
+	}
+	return missing
+}
+
+// Get returns the current assessment of an activity in the snapshot.
+func (doc *AssessmentDoc) Get(id string) (ActivityAssessment, bool) {
+	entries := doc.entries()
+	if entries == nil || entries.Object(id) == nil {
+		return ActivityAssessment{}, false
+	}
+	var aa ActivityAssessment
+	data, err := encodeOrdered(entries.Object(id))
+	if err != nil || json.Unmarshal(data, &aa) != nil {
+		return ActivityAssessment{}, false
+	}
+	return aa, true
+}
+
+// Set validates and stores an activity's assessment, then records the change
+// in the changeLog entry for the snapshot's date (adding one if needed).
+func (doc *AssessmentDoc) Set(id string, aa ActivityAssessment) error {
+	if _, ok := doc.ds.Activity(id); !ok {
+		return fmt.Errorf("activity %s not found", id)
+	}
+	if err := doc.ds.ValidateActivityAssessment(aa); err != nil {
+		return fmt.Errorf("%s: %w", id, err)
+	}
+
+	entries := doc.entries()
+	if entries == nil {
+		entries = newOrderedObject()
+		doc.root.Set("activityAssessments", entries)
+	}
+	before, existed := doc.Get(id)
+	entry := entries.Object(id)
+	if entry == nil {
+		entry = newOrderedObject()
+		entry.inline = true
+		insertOrdered(entries, id, entry)
+	}
+	entry.Set("aiCapability", aa.AICapability)
+	entry.Set("bottleneck", aa.Bottleneck)
+	entry.Set("agiWave", json.Number(strconv.Itoa(aa.AGIWave)))
+
+	var changes []string
+	switch {
+	case !existed:
+		changes = append(changes, fmt.Sprintf("%s added (%s, %s, wave %d)", id, aa.AICapability, aa.Bottleneck, aa.AGIWave))
+	default:
+		if before.AICapability != aa.AICapability {
+			changes = append(changes, fmt.Sprintf("%s aiCapability %s -> %s", id, before.AICapability, aa.AICapability))
+		}
+		if before.Bottleneck != aa.Bottleneck {
+			changes = append(changes, fmt.Sprintf("%s bottleneck %s -> %s", id, before.Bottleneck, aa.Bottleneck))
+		}
+		if before.AGIWave != aa.AGIWave {
+			changes = append(changes, fmt.Sprintf("%s agiWave %d -> %d", id, before.AGIWave, aa.AGIWave))
+		}
+	}
+	if len(changes) > 0 {
+		doc.noteChange(strings.Join(changes, "; "))
+	}
+	return nil
+}
+
+// Save writes the snapshot atomically.
+func (doc *AssessmentDoc) Save() error {
+	data, err := encodeOrdered(doc.root)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(doc.ds.dir, doc.Path()), data)
+}
+
+// ValidateActivityAssessment checks values against the enums and wave scale in scoring.json.
+func (ds *Dataset) ValidateActivityAssessment(aa ActivityAssessment) error {
+	if !containsString(ds.CapabilityLevels(), aa.AICapability) {
+		return fmt.Errorf("invalid aiCapability %q (allowed: %s)", aa.AICapability, strings.Join(ds.CapabilityLevels(), ", "))
+	}
+	if allowed := ds.AttributeValues("bottleneck"); len(allowed) > 0 && !containsString(allowed, aa.Bottleneck) {
+		return fmt.Errorf("invalid bottleneck %q (allowed: %s)", aa.Bottleneck, strings.Join(allowed, ", "))
+	}
+	min, max := 1, 4
+	for _, attr := range ds.scoring.Attributes {
+		if attr.ShortName == "agiWave" && attr.Scale != nil {
+			min, max = int(attr.Scale.Min), int(attr.Scale.Max)
+		}
+	}
+	if aa.AGIWave < min || aa.AGIWave > max {
+		return fmt.Errorf("invalid agiWave %d (allowed: %d-%d)", aa.AGIWave, min, max)
+	}
+	return nil
+}
+
+func (doc *AssessmentDoc) entries() *orderedObject {
+	return doc.root.Object("activityAssessments")
+}
+
+// noteChange appends text to the changeLog entry for the snapshot's date
+func (doc *AssessmentDoc) noteChange(text string) {
+	if log, ok := doc.root.values["changeLog"].(*orderedArray); ok && len(log.items) > 0 {
+		if last, ok := log.items[len(log.items)-1].(*orderedObject); ok {
+			if d, _ := last.Get("date"); d == doc.Date {
+				prev, _ := last.Get("changes")
+				if s := fmt.Sprint(prev); prev != nil && s != "" {
+					text = s + "; " + text
+				}
+				last.Set("changes", text)
+				return
+			}
+		}
+	}
+	version, _ := doc.root.Get("version")
+	doc.appendChangeLog(ChangeLogEntry{Date: doc.Date, Version: fmt.Sprint(version), Changes: text})
+}
+
+func (doc *AssessmentDoc) appendChangeLog(e ChangeLogEntry) {
+	log, ok := doc.root.values["changeLog"].(*orderedArray)
+	if !ok {
+		log = &orderedArray{}
+		doc.root.Set("changeLog", log)
+	}
+	entry := newOrderedObject()
+	entry.Set("date", e.Date)
+	entry.Set("version", e.Version)
+	entry.Set("changes", e.Changes)
+	log.items = append(log.items, entry)
+}
+
+// insertOrdered adds key to obj before the first activity ID that sorts after it
+func insertOrdered(obj *orderedObject, key string, v any) {
+	obj.values[key] = v
+	for i, k := range obj.keys {
+		if k != "description" && CompareIDs(k, key) > 0 {
+			obj.keys = append(obj.keys[:i], append([]string{key}, obj.keys[i:]...)...)
+			return
+		}
+	}
+	obj.keys = append(obj.keys, key)
+}
+
+func (ds *Dataset) readOrdered(filename string) (*orderedObject, error) {
+	path := filepath.Join(ds.dir, filename)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	obj, err := parseOrdered(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return obj, nil
+}
+
+func assessmentPath(date string) string {
+	return "assessments/" + strings.TrimSuffix(filepath.Base(date), ".json") + ".json"
+}
+
+// bumpMinor turns "1.2.3" into "1.3.0"; other formats are returned unchanged
+func bumpMinor(version string) string {
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return version
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return version
+	}
+	return fmt.Sprintf("%s.%d.0", parts[0], minor+1)
+}
+
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
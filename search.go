@@ -0,0 +1,402 @@
+package haai
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SearchHit is one ranked search result.
+type SearchHit struct {
+	Activity Activity      `json:"activity"`
+	Score    float64       `json:"score"`
+	Matches  []SearchMatch `json:"matches"`
+}
+
+// SearchMatch is a piece of text that matched the query, best first. Field
+// is one of the names in SearchFields; Spans are byte offsets of the matched
+// words in Text.
+type SearchMatch struct {
+	Field string  `json:"field"`
+	Score float64 `json:"score"`
+	Text  string  `json:"text"`
+	Spans []Span  `json:"spans"`
+}
+
+// Span is a half-open byte range [Start, End).
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// SearchFields lists the searchable texts of an activity with their BM25
+// weights. Category and domain texts, including the taxonomy's inclusion and
+// exclusion criteria, are shared by every activity they contain.
+var SearchFields = []struct {
+	Name   string
+	Weight float64
+}{
+	{"name", 3},
+	{"exampleTasks", 2},
+	{"description", 1.5},
+	{"category", 1},
+	{"domain", 0.5},
+	{"inclusionCriteria", 0.5},
+	{"exclusionCriteria", 0.25},
+}
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Search ranks activities against a free-text query with BM25 over the
+// fields in SearchFields. Words are stemmed, so "wiping" also finds "wipe"
+// and "wiped". Query words with no exact match in the index tolerate typos:
+// one edit for words of 4-7 letters, two for longer words, none for shorter
+// ones. limit <= 0 returns every hit.
+func (ds *Dataset) Search(query string, limit int) []SearchHit {
+	weights := make([]float64, len(SearchFields))
+	for i, f := range SearchFields {
+		weights[i] = f.Weight
+	}
+	return ds.rank(query, weights, limit)
+}
+
+// rank scores every activity against query with the given per-field
+// weights (parallel to SearchFields) and returns hits with a positive score
+func (ds *Dataset) rank(query string, weights []float64, limit int) []SearchHit {
+	idx := ds.textIndex()
+
+	type expansion struct {
+		term   string
+		weight float64
+	}
+	var expanded []expansion
+	seen := make(map[string]int)
+	add := func(term string, weight float64) {
+		if i, ok := seen[term]; ok {
+			expanded[i].weight = math.Max(expanded[i].weight, weight)
+			return
+		}
+		seen[term] = len(expanded)
+		expanded = append(expanded, expansion{term, weight})
+	}
+	for _, t := range tokenize(query) {
+		if idx.df[t.term] > 0 {
+			add(t.term, 1)
+			continue
+		}
+		word := strings.ToLower(query[t.start:t.end])
+		budget := typoBudget(word)
+		if budget == 0 {
+			continue
+		}
+		for _, w := range idx.words {
+			if d := editDistance(word, w, budget); d <= budget {
+				add(idx.stems[w], 1/float64(1+d))
+			}
+		}
+	}
+	if len(expanded) == 0 {
+		return nil
+	}
+
+	n := float64(len(idx.docs))
+	var hits []SearchHit
+	for i, doc := range idx.docs {
+		score := 0.0
+		fieldScores := make([]float64, len(doc))
+		fieldTerms := make([]map[string]bool, len(doc))
+		for _, e := range expanded {
+			df := float64(idx.df[e.term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			for f := range doc {
+				tf := float64(doc[f].tf[e.term])
+				if tf == 0 {
+					continue
+				}
+				norm := 1 - bm25B + bm25B*float64(doc[f].length)/idx.avgLen[f]
+				s := idf * e.weight * weights[f] * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+				score += s
+				fieldScores[f] += s
+				if fieldTerms[f] == nil {
+					fieldTerms[f] = make(map[string]bool)
+				}
+				fieldTerms[f][e.term] = true
+			}
+		}
+		if score <= 0 {
+			continue
+		}
+
+		hit := SearchHit{Activity: ds.activities[i], Score: score}
+		var fields []int
+		for f, s := range fieldScores {
+			if s != 0 {
+				fields = append(fields, f)
+			}
+		}
+		sort.SliceStable(fields, func(a, b int) bool { return fieldScores[fields[a]] > fieldScores[fields[b]] })
+		for _, f := range fields {
+			m := doc[f].bestMatch(SearchFields[f].Name, fieldTerms[f])
+			m.Score = fieldScores[f]
+			hit.Matches = append(hit.Matches, m)
+		}
+		hits = append(hits, hit)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return CompareIDs(hits[i].Activity.ID, hits[j].Activity.ID) < 0
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// Highlight returns Text with every matched span wrapped in open and close.
+// If width > 0 and the text is longer, it is cut to about width bytes around
+// the first match, with "..." marking the cuts.
+func (m SearchMatch) Highlight(open, close string, width int) string {
+	start, end := 0, len(m.Text)
+	if width > 0 && len(m.Text) > width && len(m.Spans) > 0 {
+		first := m.Spans[0]
+		start = first.Start - width/3
+		if start <= 0 {
+			start = 0
+		} else if sp := strings.IndexByte(m.Text[start:first.Start], ' '); sp >= 0 {
+			start += sp + 1
+		} else {
+			start = first.Start
+		}
+		end = start + width
+		if end < first.End {
+			end = first.End
+		}
+		if end >= len(m.Text) {
+			end = len(m.Text)
+		} else if sp := strings.LastIndexByte(m.Text[first.End:end], ' '); sp >= 0 {
+			end = first.End + sp
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("...")
+	}
+	pos := start
+	for _, s := range m.Spans {
+		if s.Start < start || s.End > end {
+			continue
+		}
+		b.WriteString(m.Text[pos:s.Start])
+		b.WriteString(open)
+		b.WriteString(m.Text[s.Start:s.End])
+		b.WriteString(close)
+		pos = s.End
+	}
+	b.WriteString(m.Text[pos:end])
+	if end < len(m.Text) {
+		b.WriteString("...")
+	}
+	return b.String()
+}
+
+// searchIndex is an inverted index over the activities' SearchFields
+type searchIndex struct {
+	docs   [][]indexedField // per activity, per field
+	df     map[string]int   // stem -> number of activities containing it
+	avgLen []float64        // average token count per field
+	words  []string         // distinct lowercase words, for typo matching
+	stems  map[string]string
+}
+
+type indexedField struct {
+	texts  []indexedText
+	tf     map[string]int
+	length int
+}
+
+type indexedText struct {
+	text   string
+	tokens []token
+}
+
+// bestMatch picks the text with the most matched words and marks them
+func (f *indexedField) bestMatch(field string, terms map[string]bool) SearchMatch {
+	best, bestCount := 0, -1
+	for i, t := range f.texts {
+		count := 0
+		for _, tok := range t.tokens {
+			if terms[tok.term] {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = i, count
+		}
+	}
+	m := SearchMatch{Field: field, Text: f.texts[best].text}
+	for _, tok := range f.texts[best].tokens {
+		if terms[tok.term] {
+			m.Spans = append(m.Spans, Span{tok.start, tok.end})
+		}
+	}
+	return m
+}
+
+// textIndex builds the search index on first use
+func (ds *Dataset) textIndex() *searchIndex {
+	ds.searchOnce.Do(func() {
+		idx := &searchIndex{
+			df:     make(map[string]int),
+			avgLen: make([]float64, len(SearchFields)),
+			stems:  make(map[string]string),
+		}
+		for i := range ds.activities {
+			doc := make([]indexedField, len(SearchFields))
+			inDoc := make(map[string]bool)
+			for f, texts := range ds.searchTexts(&ds.activities[i]) {
+				field := indexedField{tf: make(map[string]int)}
+				for _, text := range texts {
+					toks := tokenize(text)
+					field.texts = append(field.texts, indexedText{text, toks})
+					field.length += len(toks)
+					for _, tok := range toks {
+						field.tf[tok.term]++
+						inDoc[tok.term] = true
+						idx.stems[strings.ToLower(text[tok.start:tok.end])] = tok.term
+					}
+				}
+				idx.avgLen[f] += float64(field.length)
+				doc[f] = field
+			}
+			for term := range inDoc {
+				idx.df[term]++
+			}
+			idx.docs = append(idx.docs, doc)
+		}
+		for f := range idx.avgLen {
+			idx.avgLen[f] /= math.Max(1, float64(len(idx.docs)))
+			if idx.avgLen[f] == 0 {
+				idx.avgLen[f] = 1
+			}
+		}
+		idx.words = sortedKeys(idx.stems)
+		ds.search = idx
+	})
+	return ds.search
+}
+
+// searchTexts returns the activity's texts for each of SearchFields
+func (ds *Dataset) searchTexts(a *Activity) [][]string {
+	texts := make([][]string, len(SearchFields))
+	texts[0] = []string{a.Name}
+	texts[1] = a.ExampleTasks
+	texts[2] = []string{a.Description}
+	if c, ok := ds.Category(a.CategoryID); ok {
+		texts[3] = []string{c.Name, c.Description}
+	}
+	if d, ok := ds.Domain(DomainFromID(a.ID)); ok {
+		texts[4] = []string{d.Name, d.Description}
+		texts[5] = d.InclusionCriteria
+		texts[6] = d.ExclusionCriteria
+	}
+	return texts
+}
+
+// token is a stemmed word and its byte range in the source text
+type token struct {
+	term       string
+	start, end int
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "into": true,
+	"is": true, "it": true, "its": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "their": true, "to": true, "vs": true,
+	"with": true, "without": true,
+}
+
+// tokenize splits text into lowercase stemmed words, dropping stop words
+// and single letters
+func tokenize(text string) []token {
+	var out []token
+	emit := func(start, end int) {
+		word := strings.ToLower(text[start:end])
+		if stopWords[word] || (len(word) == 1 && !unicode.IsDigit(rune(word[0]))) {
+			return
+		}
+		out = append(out, token{stem(word), start, end})
+	}
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+		} else if start >= 0 {
+			emit(start, i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		emit(start, len(text))
+	}
+	return out
+}
+
+// typoBudget is the number of edits tolerated for a query word
+func typoBudget(word string) int {
+	switch n := len([]rune(word)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b (insertions, deletions, substitutions and adjacent transpositions), or
+// maxEdits+1 once it is known to exceed maxEdits
+func editDistance(a, b string, maxEdits int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > maxEdits || -d > maxEdits {
+		return maxEdits + 1
+	}
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > maxEdits {
+			return maxEdits + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
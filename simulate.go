@@ -0,0 +1,307 @@
+package haai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+)
+
+// Adoption curve models
+const (
+	CurveLogistic = "logistic"
+	CurveBass     = "bass"
+)
+
+// AdoptionCurve is the share of a wave's activities automated over time.
+// The logistic curve is centred on the middle of the wave window; the Bass
+// diffusion curve starts at the window's first year. Shift moves the window
+// by that many years (negative is earlier) and Ceiling caps the share. The
+// numeric fields are pointers so that an override can set them to 0.
+type AdoptionCurve struct {
+	Model      string   `json:"model,omitempty"`     // logistic or bass
+	Ceiling    *float64 `json:"ceiling,omitempty"`   // long-run share, 0-1 (default 1)
+	Shift      *float64 `json:"shift,omitempty"`     // years added to the wave window
+	Steepness  *float64 `json:"steepness,omitempty"` // logistic growth rate per year
+	Innovation *float64 `json:"p,omitempty"`         // Bass coefficient of innovation
+	Imitation  *float64 `json:"q,omitempty"`         // Bass coefficient of imitation
+}
+
+// merge returns c with the fields o sets
+func (c AdoptionCurve) merge(o AdoptionCurve) AdoptionCurve {
+	if o.Model != "" {
+		c.Model = o.Model
+	}
+	for _, f := range []struct{ dst, src **float64 }{
+		{&c.Ceiling, &o.Ceiling},
+		{&c.Shift, &o.Shift},
+		{&c.Steepness, &o.Steepness},
+		{&c.Innovation, &o.Innovation},
+		{&c.Imitation, &o.Imitation},
+	} {
+		if *f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	return c
+}
+
+// valueOr returns *p, or def if p is not set
+func valueOr(p *float64, def float64) float64 {
+	if p == nil {
+		return def
+	}
+	return *p
+}
+
+func (c AdoptionCurve) validate() error {
+	if ceiling := valueOr(c.Ceiling, 1); ceiling < 0 || ceiling > 1 {
+		return fmt.Errorf("ceiling %g is outside 0-1", ceiling)
+	}
+	switch c.Model {
+	case CurveLogistic:
+		if valueOr(c.Steepness, 0) <= 0 {
+			return fmt.Errorf("logistic curve needs a positive steepness")
+		}
+	case CurveBass:
+		if valueOr(c.Innovation, 0) <= 0 || valueOr(c.Imitation, 0) < 0 {
+			return fmt.Errorf("bass curve needs p > 0 and q >= 0")
+		}
+	default:
+		return fmt.Errorf("unknown curve model %q (use %s or %s)", c.Model, CurveLogistic, CurveBass)
+	}
+	return nil
+}
+
+// Share returns the automated share at time t (in years, e.g. 2027.5) for a
+// wave window. Open-ended windows are taken to last openYears.
+func (c AdoptionCurve) Share(w WaveWindow, openYears int, t float64) float64 {
+	ceiling := valueOr(c.Ceiling, 1)
+	shift := valueOr(c.Shift, 0)
+	start := float64(w.Start) + shift
+	end := float64(w.End+1) + shift
+	if w.End == 0 {
+		end = start + float64(openYears)
+	}
+	switch c.Model {
+	case CurveLogistic:
+		mid := (start + end) / 2
+		return ceiling / (1 + math.Exp(-valueOr(c.Steepness, 0)*(t-mid)))
+	case CurveBass:
+		tau := t - start
+		if tau <= 0 {
+			return 0
+		}
+		p, q := valueOr(c.Innovation, 0), valueOr(c.Imitation, 0)
+		e := math.Exp(-(p + q) * tau)
+		return ceiling * (1 - e) / (1 + q/p*e)
+	}
+	return 0
+}
+
+// ScenariosFile is scenarios.json: named adoption scenarios for Simulate.
+type ScenariosFile struct {
+	Version         string     `json:"version"`
+	Description     string     `json:"description"`
+	OpenWindowYears int        `json:"openWindowYears"` // assumed length of a window such as "2032+"
+	Scenarios       []Scenario `json:"scenarios"`
+}
+
+// Scenario is an adoption curve for every wave, with optional overrides per
+// wave and per domain. An override only replaces the fields it sets, and a
+// domain override applies on top of its wave's.
+type Scenario struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Curve       AdoptionCurve         `json:"curve"`
+	Waves       map[int]AdoptionCurve `json:"waves,omitempty"`
+	Domains     map[int]AdoptionCurve `json:"domains,omitempty"`
+}
+
+// CurveFor returns the curve for an activity in the given wave and domain.
+func (s *Scenario) CurveFor(wave, domain int) AdoptionCurve {
+	return s.Curve.merge(s.Waves[wave]).merge(s.Domains[domain])
+}
+
+// Scenarios returns the scenarios in scenarios.json.
+func (ds *Dataset) Scenarios() (*ScenariosFile, error) {
+	var sf ScenariosFile
+	if err := ds.loadJSON("scenarios.json", &sf); err != nil {
+		return nil, err
+	}
+	return &sf, sf.check("scenarios.json")
+}
+
+// ReadScenarios reads a scenarios file in the shape of scenarios.json.
+func ReadScenarios(path string) (*ScenariosFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sf ScenariosFile
+	if err := json.Unmarshal(data, &sf); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &sf, sf.check(path)
+}
+
+// check validates every curve a scenario can produce
+func (sf *ScenariosFile) check(file string) error {
+	if len(sf.Scenarios) == 0 {
+		return fmt.Errorf("%s: no scenarios", file)
+	}
+	if sf.OpenWindowYears <= 0 {
+		return fmt.Errorf("%s: openWindowYears must be positive", file)
+	}
+	seen := make(map[string]bool)
+	for _, s := range sf.Scenarios {
+		if s.Name == "" || seen[s.Name] {
+			return fmt.Errorf("%s: scenario names must be unique and not empty (%q)", file, s.Name)
+		}
+		seen[s.Name] = true
+		if s.Curve.Ceiling != nil && *s.Curve.Ceiling == 0 {
+			return fmt.Errorf("%s: scenario %s: curve ceiling must be above 0 (set 0 in a wave or domain override instead)", file, s.Name)
+		}
+		waves, domains := []int{0}, []int{0}
+		for w := range s.Waves {
+			waves = append(waves, w)
+		}
+		for d := range s.Domains {
+			domains = append(domains, d)
+		}
+		sort.Ints(waves)
+		sort.Ints(domains)
+		for _, w := range waves {
+			for _, d := range domains {
+				if err := s.CurveFor(w, d).validate(); err != nil {
+					where := "scenario " + s.Name
+					if w > 0 {
+						where += fmt.Sprintf(", wave %d", w)
+					}
+					if d > 0 {
+						where += fmt.Sprintf(", domain %d", d)
+					}
+					return fmt.Errorf("%s: %s: %w", file, where, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Find returns the scenario with the given name.
+func (sf *ScenariosFile) Find(name string) (*Scenario, bool) {
+	for i := range sf.Scenarios {
+		if sf.Scenarios[i].Name == name {
+			return &sf.Scenarios[i], true
+		}
+	}
+	return nil, false
+}
+
+// SimulationYear is one year of a scenario, evaluated at mid-year.
+type SimulationYear struct {
+	Year          int             `json:"year"`
+	ActivityShare float64         `json:"activityShare"` // mean automated share over all activities
+	Minutes       float64         `json:"minutes"`       // ATUS minutes per day automated
+	ValueBillions float64         `json:"valueBillions"` // annual domain value automated
+	Waves         map[int]float64 `json:"waves"`         // mean automated share per agiWave
+}
+
+// ScenarioResult is a scenario's year-by-year projection.
+type ScenarioResult struct {
+	Scenario    string           `json:"scenario"`
+	Description string           `json:"description"`
+	Years       []SimulationYear `json:"years"`
+}
+
+// Simulation projects scenarios over a range of years. Every activity is
+// automated along its scenario's curve for its wave window; the totals give
+// the shares against which the minutes and value are measured.
+type Simulation struct {
+	From               int              `json:"from"`
+	To                 int              `json:"to"`
+	Windows            []WaveWindow     `json:"windows"`
+	Activities         int              `json:"activities"`
+	TotalMinutes       float64          `json:"totalMinutes"`
+	TotalValueBillions float64          `json:"totalValueBillions"`
+	Scenarios          []ScenarioResult `json:"scenarios"`
+}
+
+// Simulate projects the named scenarios (all of them if names is empty) from
+// one year to another using the wave windows of the current assessment, the
+// ATUS minutes allocated to each activity and the annual value in
+// domainEconomics.
+func (ds *Dataset) Simulate(sf *ScenariosFile, names []string, from, to int) (*Simulation, error) {
+	if to < from {
+		return nil, fmt.Errorf("simulation ends (%d) before it starts (%d)", to, from)
+	}
+	scenarios := sf.Scenarios
+	if len(names) > 0 {
+		scenarios = nil
+		for _, name := range names {
+			s, ok := sf.Find(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown scenario %q", name)
+			}
+			scenarios = append(scenarios, *s)
+		}
+	}
+	af := ds.Assessment()
+	if af == nil {
+		return nil, fmt.Errorf("no assessment with agiWaveTimelines loaded")
+	}
+	windows := af.WaveWindows()
+	alloc, err := ds.AllocateATUS(nil)
+	if err != nil {
+		return nil, fmt.Errorf("mappings.json: %w", err)
+	}
+
+	sim := &Simulation{From: from, To: to, Activities: len(ds.activities), TotalMinutes: alloc.TotalMinutes - alloc.Unallocated}
+	for _, w := range windows {
+		sim.Windows = append(sim.Windows, w)
+	}
+	sort.Slice(sim.Windows, func(i, j int) bool { return sim.Windows[i].Wave < sim.Windows[j].Wave })
+
+	// Each domain's value is spread evenly over its activities
+	value := make(map[int]float64)
+	counts := make(map[int]int)
+	waveCounts := make(map[int]int)
+	for _, d := range ds.mappings.EconomicImpact.DomainEcon {
+		value[d.DomainID] = float64(d.AnnualValueBillions)
+		sim.TotalValueBillions += value[d.DomainID]
+	}
+	for _, a := range ds.activities {
+		counts[a.DomainID]++
+		waveCounts[a.Scores.AGIWave]++
+		if _, ok := windows[a.Scores.AGIWave]; !ok && a.Scores.AGIWave > 0 {
+			return nil, fmt.Errorf("%s: no agiWaveTimelines window for wave %d", a.ID, a.Scores.AGIWave)
+		}
+	}
+
+	for _, s := range scenarios {
+		r := ScenarioResult{Scenario: s.Name, Description: s.Description}
+		for year := from; year <= to; year++ {
+			y := SimulationYear{Year: year, Waves: make(map[int]float64)}
+			t := float64(year) + 0.5
+			for _, a := range ds.activities {
+				w, ok := windows[a.Scores.AGIWave]
+				if !ok {
+					continue
+				}
+				share := s.CurveFor(a.Scores.AGIWave, a.DomainID).Share(w, sf.OpenWindowYears, t)
+				y.ActivityShare += share
+				y.Waves[a.Scores.AGIWave] += share / float64(waveCounts[a.Scores.AGIWave])
+				y.Minutes += share * alloc.Activities[a.ID]
+				y.ValueBillions += share * value[a.DomainID] / float64(counts[a.DomainID])
+			}
+			if len(ds.activities) > 0 {
+				y.ActivityShare /= float64(len(ds.activities))
+			}
+			r.Years = append(r.Years, y)
+		}
+		sim.Scenarios = append(sim.Scenarios, r)
+	}
+	return sim, nil
+}
@@ -0,0 +1,204 @@
+package haai
+
+import "sort"
+
+// stem reduces an English word to its Porter stem ("wiping" -> "wipe",
+// "invoices" -> "invoic"), so different inflections of a word match in search.
+// Words must be lowercase; anything that is not plain ASCII is returned as is.
+func stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+	s := &stemmer{b: []byte(word)}
+	s.step1a()
+	s.step1b()
+	s.step1c()
+	s.replace(step2Rules, 0)
+	s.replace(step3Rules, 0)
+	s.step4()
+	s.step5()
+	return string(s.b)
+}
+
+type stemmer struct {
+	b []byte
+}
+
+// cons reports whether b[i] is a consonant
+func (s *stemmer) cons(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !s.cons(i-1)
+	}
+	return true
+}
+
+// measure counts the vowel-consonant sequences in b[:n]
+func (s *stemmer) measure(n int) int {
+	m, i := 0, 0
+	for i < n && s.cons(i) {
+		i++
+	}
+	for i < n {
+		for i < n && !s.cons(i) {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		for i < n && s.cons(i) {
+			i++
+		}
+		m++
+	}
+	return m
+}
+
+func (s *stemmer) hasVowel(n int) bool {
+	for i := 0; i < n; i++ {
+		if !s.cons(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubleCons reports whether b[:n] ends in a double consonant
+func (s *stemmer) doubleCons(n int) bool {
+	return n >= 2 && s.b[n-1] == s.b[n-2] && s.cons(n-1)
+}
+
+// cvc reports whether b[:n] ends consonant-vowel-consonant, the last not w, x or y
+func (s *stemmer) cvc(n int) bool {
+	if n < 3 || !s.cons(n-3) || s.cons(n-2) || !s.cons(n-1) {
+		return false
+	}
+	c := s.b[n-1]
+	return c != 'w' && c != 'x' && c != 'y'
+}
+
+func (s *stemmer) ends(suffix string) bool {
+	return len(s.b) >= len(suffix) && string(s.b[len(s.b)-len(suffix):]) == suffix
+}
+
+func (s *stemmer) step1a() {
+	switch {
+	case s.ends("sses"), s.ends("ies"):
+		s.b = s.b[:len(s.b)-2]
+	case s.ends("ss"):
+	case s.ends("s"):
+		s.b = s.b[:len(s.b)-1]
+	}
+}
+
+func (s *stemmer) step1b() {
+	n := len(s.b)
+	switch {
+	case s.ends("eed"):
+		if s.measure(n-3) > 0 {
+			s.b = s.b[:n-1]
+		}
+		return
+	case s.ends("ed") && s.hasVowel(n-2):
+		s.b = s.b[:n-2]
+	case s.ends("ing") && s.hasVowel(n-3):
+		s.b = s.b[:n-3]
+	default:
+		return
+	}
+	n = len(s.b)
+	switch {
+	case s.ends("at"), s.ends("bl"), s.ends("iz"):
+		s.b = append(s.b, 'e')
+	case s.doubleCons(n) && s.b[n-1] != 'l' && s.b[n-1] != 's' && s.b[n-1] != 'z':
+		s.b = s.b[:n-1]
+	case s.measure(n) == 1 && s.cvc(n):
+		s.b = append(s.b, 'e')
+	}
+}
+
+func (s *stemmer) step1c() {
+	if s.ends("y") && s.hasVowel(len(s.b)-1) {
+		s.b[len(s.b)-1] = 'i'
+	}
+}
+
+type suffixRule struct {
+	suffix, replacement string
+}
+
+var step2Rules = longestFirst([]suffixRule{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+	{"izer", "ize"}, {"bli", "ble"}, {"alli", "al"}, {"entli", "ent"},
+	{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+	{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+	{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+	{"logi", "log"},
+})
+
+var step3Rules = longestFirst([]suffixRule{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+	{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+})
+
+var step4Suffixes = longestFirst([]suffixRule{
+	{"al", ""}, {"ance", ""}, {"ence", ""}, {"er", ""}, {"ic", ""},
+	{"able", ""}, {"ible", ""}, {"ant", ""}, {"ement", ""}, {"ment", ""},
+	{"ent", ""}, {"ion", ""}, {"ou", ""}, {"ism", ""}, {"ate", ""},
+	{"iti", ""}, {"ous", ""}, {"ive", ""}, {"ize", ""},
+})
+
+// longestFirst orders rules so the longest matching suffix is tried first
+func longestFirst(rules []suffixRule) []suffixRule {
+	sort.SliceStable(rules, func(i, j int) bool { return len(rules[i].suffix) > len(rules[j].suffix) })
+	return rules
+}
+
+// replace applies the rule for the longest matching suffix if the remaining
+// stem has a measure above minMeasure; at most one rule applies
+func (s *stemmer) replace(rules []suffixRule, minMeasure int) {
+	for _, r := range rules {
+		if !s.ends(r.suffix) {
+			continue
+		}
+		n := len(s.b) - len(r.suffix)
+		if s.measure(n) > minMeasure {
+			s.b = append(s.b[:n], r.replacement...)
+		}
+		return
+	}
+}
+
+func (s *stemmer) step4() {
+	for _, r := range step4Suffixes {
+		if !s.ends(r.suffix) {
+			continue
+		}
+		n := len(s.b) - len(r.suffix)
+		if r.suffix == "ion" && (n == 0 || (s.b[n-1] != 's' && s.b[n-1] != 't')) {
+			return
+		}
+		if s.measure(n) > 1 {
+			s.b = s.b[:n]
+		}
+		return
+	}
+}
+
+func (s *stemmer) step5() {
+	if n := len(s.b) - 1; s.ends("e") {
+		if m := s.measure(n); m > 1 || (m == 1 && !s.cvc(n)) {
+			s.b = s.b[:n]
+		}
+	}
+	if n := len(s.b); s.measure(n) > 1 && s.doubleCons(n) && s.b[n-1] == 'l' {
+		s.b = s.b[:n-1]
+	}
+}
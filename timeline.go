@@ -0,0 +1,60 @@
+package haai
+
+import (
+	"strconv"
+	"strings"
+)
+
+// WaveWindow is a parsed agiWaveTimelines entry such as "2026-2028" or "2032+".
+// End is 0 for open-ended windows.
+type WaveWindow struct {
+	Wave  int `json:"wave"`
+	Start int `json:"start"`
+	End   int `json:"end,omitempty"`
+}
+
+// Contains reports whether the given year falls inside the window.
+func (w WaveWindow) Contains(year int) bool {
+	return year >= w.Start && (w.End == 0 || year <= w.End)
+}
+
+func (w WaveWindow) String() string {
+	if w.End == 0 {
+		return strconv.Itoa(w.Start) + "+"
+	}
+	if w.End == w.Start {
+		return strconv.Itoa(w.Start)
+	}
+	return strconv.Itoa(w.Start) + "-" + strconv.Itoa(w.End)
+}
+
+// ParseWaveWindow parses "2024-2026", "2032+" or a single year.
+func ParseWaveWindow(s string) (WaveWindow, bool) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "+") {
+		start, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(s, "+")))
+		return WaveWindow{Start: start}, err == nil
+	}
+	if lo, hi, ok := strings.Cut(s, "-"); ok {
+		start, err1 := strconv.Atoi(strings.TrimSpace(lo))
+		end, err2 := strconv.Atoi(strings.TrimSpace(hi))
+		if err1 != nil || err2 != nil || end < start {
+			return WaveWindow{}, false
+		}
+		return WaveWindow{Start: start, End: end}, true
+	}
+	year, err := strconv.Atoi(s)
+	return WaveWindow{Start: year, End: year}, err == nil
+}
+
+// WaveWindows parses every wave timeline in the assessment, keyed by wave.
+func (af *AssessmentFile) WaveWindows() map[int]WaveWindow {
+	windows := make(map[int]WaveWindow)
+	for _, w := range af.AGIWaveTimelines.Waves {
+		if win, ok := ParseWaveWindow(w.Timeline); ok {
+			win.Wave = w.Wave
+			windows[w.Wave] = win
+		}
+	}
+	return windows
+}
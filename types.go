@@ -0,0 +1,541 @@
+package haai
+
+import "encoding/json"
+
+// Data structures for taxonomy
+type Taxonomy struct {
+	Domains             []Domain            `json:"domains"`
+	ClassificationRules ClassificationRules `json:"classificationRules"`
+}
+
+// ClassificationRules holds the taxonomy's guidance for ambiguous activities
+type ClassificationRules struct {
+	MultiDomainActivities MultiDomainActivities `json:"multiDomainActivities"`
+	BoundaryRules         []BoundaryRule        `json:"boundaryRules"`
+	ContextModifiers      ContextModifiers      `json:"contextModifiers"`
+}
+
+type MultiDomainActivities struct {
+	Principle string               `json:"principle"`
+	Examples  []MultiDomainExample `json:"examples"`
+}
+
+type MultiDomainExample struct {
+	Activity   string `json:"activity"`
+	AppearsAs  string `json:"appearsAs"`
+	ClassifyAs string `json:"classifyAs"` // category ID followed by its name
+	Rationale  string `json:"rationale"`
+}
+
+type BoundaryRule struct {
+	Boundary string `json:"boundary"` // e.g. "Domain 4 vs 5"
+	Rule     string `json:"rule"`
+}
+
+type ContextModifiers struct {
+	Description string            `json:"description"`
+	Examples    []ContextModifier `json:"examples"`
+}
+
+type ContextModifier struct {
+	Action              string `json:"action"`
+	ProfessionalContext string `json:"professionalContext"`
+	PersonalContext     string `json:"personalContext"`
+}
+
+type Domain struct {
+	ID                  int        `json:"id"`
+	Name                string     `json:"name"`
+	Description         string     `json:"description"`
+	AbstractionScore    int        `json:"abstractionScore"`
+	EstimatedAgiWave    any        `json:"estimatedAgiWave"` // can be int or []int
+	PrimaryAISystemType string     `json:"primaryAiSystemType"`
+	InclusionCriteria   []string   `json:"inclusionCriteria,omitempty"`
+	ExclusionCriteria   []string   `json:"exclusionCriteria,omitempty"`
+	Categories          []Category `json:"categories"`
+}
+
+type Category struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Scoring definitions (scoring.json)
+type Scoring struct {
+	Version         string          `json:"version"`
+	DataModel       DataModel       `json:"dataModel"`
+	Attributes      []Attribute     `json:"attributes"`
+	CompositeScores CompositeScores `json:"compositeScores"`
+}
+
+// Attribute is a scoring dimension; categorical ones list their allowed values.
+// Scaled attributes are ordinal unless Type is "nominal" (unordered codes).
+type Attribute struct {
+	ID        int              `json:"id"`
+	Name      string           `json:"name"`
+	ShortName string           `json:"shortName"`
+	Type      string           `json:"type,omitempty"`
+	Scale     *ScoreRange      `json:"scale,omitempty"`
+	Values    []AttributeValue `json:"values,omitempty"`
+}
+
+type AttributeValue struct {
+	Value       string `json:"value"`
+	Definition  string `json:"definition,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type DataModel struct {
+	Version        string     `json:"version"`
+	ActivitiesFile string     `json:"activitiesFile"`
+	IndicesDir     string     `json:"indicesDir"`
+	AssessmentsDir string     `json:"assessmentsDir"`
+	Indices        []IndexRef `json:"indices"`
+}
+
+type IndexRef struct {
+	IndexID     string `json:"indexId"`
+	File        string `json:"file"`
+	Description string `json:"description"`
+}
+
+type CompositeScores struct {
+	Note                string                 `json:"note"`
+	AutomationReadiness AutomationReadinessDef `json:"automationReadiness"`
+	HumanEssentiality   HumanEssentialityDef   `json:"humanEssentiality"`
+}
+
+type AutomationReadinessDef struct {
+	Description       string             `json:"description"`
+	Formula           string             `json:"formula"`
+	CapabilityMapping map[string]float64 `json:"capabilityMapping"`
+	Range             ScoreRange         `json:"range"`
+	Interpretation    map[string]string  `json:"interpretation"`
+}
+
+type HumanEssentialityDef struct {
+	Description string               `json:"description"`
+	Note        string               `json:"note"`
+	Factors     []EssentialityFactor `json:"factors"`
+}
+
+// EssentialityFactor is one machine-readable humanEssentiality rule
+type EssentialityFactor struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	Weight      float64   `json:"weight"`
+	When        Condition `json:"when"`
+}
+
+type ScoreRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// Index file structure (v2.0.0 data model)
+type IndexFile struct {
+	IndexID     string         `json:"indexId"`
+	IndexName   string         `json:"indexName"`
+	Description string         `json:"description"`
+	Version     string         `json:"version"`
+	Scale       IndexScale     `json:"scale"`
+	Values      map[string]int `json:"values"`
+}
+
+type IndexScale struct {
+	Min    int          `json:"min"`
+	Max    int          `json:"max"`
+	Levels []IndexLevel `json:"levels"`
+}
+
+type IndexLevel struct {
+	Level      int    `json:"level"`
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// Activities file structure (v2.0.0)
+type ActivitiesFile struct {
+	Version         string     `json:"version"`
+	ActivitiesCount int        `json:"activitiesCount"`
+	Activities      []Activity `json:"activities"`
+}
+
+// Assessment file structure
+type AssessmentFile struct {
+	AssessmentDate      string                     `json:"assessmentDate"`
+	Version             string                     `json:"version"`
+	AGIWaveTimelines    AGIWaveTimelines           `json:"agiWaveTimelines"`
+	CompositeWeights    *CompositeWeights          `json:"compositeWeights,omitempty"`
+	ActivityAssessments map[string]json.RawMessage `json:"activityAssessments"`
+	ChangeLog           []ChangeLogEntry           `json:"changeLog,omitempty"`
+}
+
+type ChangeLogEntry struct {
+	Date    string `json:"date"`
+	Version string `json:"version"`
+	Changes string `json:"changes"`
+}
+
+type CompositeWeights struct {
+	Description         string             `json:"description"`
+	AutomationReadiness ReadinessWeights   `json:"automationReadiness"`
+	CapabilityMapping   map[string]float64 `json:"capabilityMapping"`
+}
+
+type ReadinessWeights struct {
+	Abstraction             float64 `json:"abstraction"`
+	ErrorTolerance          float64 `json:"errorTolerance"`
+	InterpersonalComplexity float64 `json:"interpersonalComplexity"`
+	AICapability            float64 `json:"aiCapability"`
+}
+
+type AGIWaveTimelines struct {
+	Description string     `json:"description"`
+	Waves       []WaveInfo `json:"waves"`
+}
+
+type WaveInfo struct {
+	Wave            int      `json:"wave"`
+	Timeline        string   `json:"timeline"`
+	Characteristics string   `json:"characteristics"`
+	TypicalDomains  []string `json:"typicalDomains"`
+	KeyCapabilities []string `json:"keyCapabilities"`
+}
+
+type ActivityAssessment struct {
+	AICapability string        `json:"aiCapability"`
+	Bottleneck   string        `json:"bottleneck"`
+	AGIWave      int           `json:"agiWave"`
+	Forecast     *WaveForecast `json:"forecast,omitempty"`
+}
+
+// WaveForecast is an optional distribution over when an activity is
+// automated: either probabilities per AGI wave or earliest, likely and
+// latest years (a triangular distribution).
+type WaveForecast struct {
+	WaveProbabilities map[int]float64 `json:"waveProbabilities,omitempty"`
+	Earliest          float64         `json:"earliest,omitempty"`
+	Likely            float64         `json:"likely,omitempty"`
+	Latest            float64         `json:"latest,omitempty"`
+}
+
+// Activity from domain files
+type DomainFile struct {
+	DomainID   int        `json:"domainId"`
+	DomainName string     `json:"domainName"`
+	Activities []Activity `json:"activities"`
+}
+
+type Activity struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	DomainID     int      `json:"domainId"`
+	CategoryID   string   `json:"categoryId"`
+	Scores       Scores   `json:"scores"`
+	ExampleTasks []string `json:"exampleTasks"`
+}
+
+type Scores struct {
+	Abstraction             int    `json:"abstraction"`
+	ErrorTolerance          int    `json:"errorTolerance"`
+	FeedbackSpeed           int    `json:"feedbackSpeed"`
+	InterpersonalComplexity int    `json:"interpersonalComplexity"`
+	Purpose                 int    `json:"purpose"`
+	AICapability            string `json:"aiCapability"`
+	Bottleneck              string `json:"bottleneck"`
+	AGIWave                 int    `json:"agiWave"`
+
+	// Custom holds values from indices without a dedicated field, keyed by index ID
+	Custom map[string]int `json:"custom,omitempty"`
+
+	// Composite scores computed from the above using the assessment's weights
+	// and the humanEssentiality rules in scoring.json
+	AutomationReadiness float64 `json:"automationReadiness"`
+	HumanEssentiality   float64 `json:"humanEssentiality"`
+}
+
+// Mappings data
+type Mappings struct {
+	Sources               map[string]MappingSource `json:"sources"`
+	ONETMapping           ONETMapping              `json:"onetMapping"`
+	ATUSMapping           ATUSMapping              `json:"atusMapping"`
+	ISCOMapping           ISCOMapping              `json:"iscoMapping"`
+	Behavior1KMapping     Behavior1KMapping        `json:"behavior1kMapping"`
+	ActivityNetMapping    ActivityNetMapping       `json:"activityNetMapping"`
+	EconomicImpact        EconomicImpact           `json:"economicImpact"`
+	AICapabilityAlignment AICapabilityAlignment    `json:"aiCapabilityAlignment"`
+}
+
+// MappingSource describes an external classification in mappings.json.
+type MappingSource struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url,omitempty"`
+	Version     string `json:"version,omitempty"`
+}
+
+// ONETMapping maps groups of O*NET Generalized Work Activities to HAAI
+// domains and categories.
+type ONETMapping struct {
+	Description string      `json:"description"`
+	Mappings    []ONETEntry `json:"mappings"`
+}
+
+type ONETEntry struct {
+	ONETCategory   string    `json:"onetCategory"`
+	ONETActivities []string  `json:"onetActivities"`
+	HAAIDomains    []HAAIRef `json:"haaiDomains"` // domains and categories
+}
+
+// ISCOMapping maps ISCO-08 major occupation groups to HAAI domains and
+// categories.
+type ISCOMapping struct {
+	Description string      `json:"description"`
+	Mappings    []ISCOEntry `json:"mappings"`
+}
+
+type ISCOEntry struct {
+	ISCOGroup      int       `json:"iscoGroup"`
+	ISCOName       string    `json:"iscoName"`
+	SampleTasks    []string  `json:"sampleTasks"`
+	HAAICategories []HAAIRef `json:"haaiCategories"` // categories and domains
+}
+
+// Behavior1KMapping maps BEHAVIOR-1K household robotics task categories to
+// HAAI categories.
+type Behavior1KMapping struct {
+	Description string            `json:"description"`
+	Mappings    []Behavior1KEntry `json:"mappings"`
+}
+
+type Behavior1KEntry struct {
+	BehaviorCategory string    `json:"behaviorCategory"`
+	HAAICategories   []HAAIRef `json:"haaiCategories"`
+	TaskExamples     []string  `json:"taskExamples"`
+}
+
+// ActivityNetMapping maps ActivityNet/Kinetics action types to HAAI domains
+// and categories.
+type ActivityNetMapping struct {
+	Description string             `json:"description"`
+	Mappings    []ActivityNetEntry `json:"mappings"`
+}
+
+type ActivityNetEntry struct {
+	ActionType  string    `json:"actionType"`
+	HAAIDomains []HAAIRef `json:"haaiDomains"` // domains and categories
+	Examples    []string  `json:"examples"`
+}
+
+// AICapabilityAlignment records the waves the taxonomy predicted for
+// deployed AI systems.
+type AICapabilityAlignment struct {
+	Description string          `json:"description"`
+	Systems     []AlignedSystem `json:"systems"`
+}
+
+type AlignedSystem struct {
+	System                   string    `json:"system"`
+	DemonstratedCapabilities []string  `json:"demonstratedCapabilities"`
+	HAAIDomains              []HAAIRef `json:"haaiDomains,omitempty"`
+	HAAICategories           []HAAIRef `json:"haaiCategories,omitempty"`
+	PredictedWave            WaveList  `json:"predictedWave"` // one wave or several
+	AlignmentStatus          string    `json:"alignmentStatus"`
+}
+
+// Refs returns the system's domain and category references.
+func (s AlignedSystem) Refs() []HAAIRef {
+	return append(append([]HAAIRef(nil), s.HAAIDomains...), s.HAAICategories...)
+}
+
+type ATUSMapping struct {
+	DataSource string      `json:"dataSource"`
+	TimeUnit   string      `json:"timeUnit"`
+	Mappings   []ATUSEntry `json:"mappings"`
+	Summary    ATUSSummary `json:"summary"`
+}
+
+type ATUSEntry struct {
+	ATUSCode          string         `json:"atusCode"`
+	ATUSCategory      string         `json:"atusCategory"`
+	HAICategories     []string       `json:"haaiCategories"`
+	Notes             string         `json:"notes"`
+	AvgMinutesPerDay  int            `json:"avgMinutesPerDay"`  // averaged over the whole population
+	ParticipationRate float64        `json:"participationRate"` // share of people doing the activity on an average day
+	Breakdown         map[string]int `json:"breakdown,omitempty"`
+
+	// BreakdownRefs maps breakdown items to the haaiCategories they belong
+	// to and HAAIWeights weights haaiCategories for minutes without a
+	// breakdown; see AllocateATUS
+	BreakdownRefs map[string][]HAAIRef `json:"breakdownRefs,omitempty"`
+	HAAIWeights   map[HAAIRef]float64  `json:"haaiWeights,omitempty"`
+}
+
+type ATUSSummary struct {
+	TotalMinutesPerDay   int `json:"totalMinutesPerDay"`
+	SleepAndPersonalCare int `json:"sleepAndPersonalCare"`
+	Work                 int `json:"work"`
+	Leisure              int `json:"leisure"`
+	HouseholdAndCare     int `json:"householdAndCare"`
+	Travel               int `json:"travel"`
+	Other                int `json:"other"`
+}
+
+type EconomicImpact struct {
+	Description   string            `json:"description"`
+	Currency      string            `json:"currency"`
+	Year          int               `json:"year"`
+	USLaborMarket USLaborMarket     `json:"usLaborMarket"`
+	DomainEcon    []DomainEconomic  `json:"domainEconomics"`
+	Projections   ImpactProjections `json:"automationImpactProjections"`
+}
+
+// ImpactProjections are the hand-entered automation impact estimates per
+// AGI wave; EconExposure checks them against the activity assessments.
+type ImpactProjections struct {
+	Description string           `json:"description"`
+	Waves       []WaveProjection `json:"waves"`
+}
+
+type WaveProjection struct {
+	Wave                  int     `json:"wave"`
+	Timeline              string  `json:"timeline"`
+	WorkersAffected       int     `json:"workersAffected"`
+	PercentOfWorkforce    float64 `json:"percentOfWorkforce"`
+	PrimaryDomains        []int   `json:"primaryDomains"`
+	EconomicValueBillions int     `json:"economicValueBillions"`
+	Notes                 string  `json:"notes"`
+}
+
+type USLaborMarket struct {
+	TotalEmployment   int     `json:"totalEmployment"`
+	TotalWages        int64   `json:"totalWages"`
+	AverageHourlyWage float64 `json:"averageHourlyWage"`
+}
+
+type DomainEconomic struct {
+	DomainID            int      `json:"domainId"`
+	DomainName          string   `json:"domainName"`
+	EstimatedWorkers    int      `json:"estimatedWorkers"`
+	PercentOfWorkforce  float64  `json:"percentOfWorkforce"`
+	MedianHourlyWage    *float64 `json:"medianHourlyWage"`
+	AnnualValueBillions int      `json:"annualValueBillions"`
+	AutomationExposure  string   `json:"automationExposure"`
+	SampleOccupations   []string `json:"sampleOccupations"`
+	Notes               string   `json:"notes"`
+}
+
+// Validation test suites (validation.json)
+type ValidationFile struct {
+	Version                 string                  `json:"version"`
+	DayInLifeCoverageTest   DayInLifeCoverageTest   `json:"dayInLifeCoverageTest"`
+	OccupationCoverageTest  OccupationCoverageTest  `json:"occupationCoverageTest"`
+	AdditionalTestScenarios AdditionalTestScenarios `json:"additionalTestScenarios"`
+	EdgeCaseTests           EdgeCaseTests           `json:"edgeCaseTests"`
+	MECEComplianceCheck     MECEComplianceCheck     `json:"meceComplianceCheck"`
+	ValidationProtocol      ValidationProtocol      `json:"validationProtocol"`
+}
+
+type DayInLifeCoverageTest struct {
+	Description string           `json:"description"`
+	TestSubject string           `json:"testSubject"`
+	Activities  []DayInLifeEntry `json:"activities"`
+}
+
+// DayInLifeEntry is one time slot. Minutes and ATUSCode are optional; a
+// slot without minutes lasts until the next slot starts.
+type DayInLifeEntry struct {
+	Time           string `json:"time"`
+	Activity       string `json:"activity"`
+	Minutes        int    `json:"minutes,omitempty"`
+	ATUSCode       string `json:"atusCode,omitempty"`
+	Classification struct {
+		CategoryID   string `json:"categoryId"`
+		CategoryName string `json:"categoryName"`
+	} `json:"classification"`
+}
+
+type OccupationCoverageTest struct {
+	Description string               `json:"description"`
+	TestCases   []OccupationTestCase `json:"testCases"`
+}
+
+type OccupationTestCase struct {
+	ISCOGroup       int      `json:"iscoGroup"`
+	OccupationGroup string   `json:"occupationGroup"`
+	SampleTasks     []string `json:"sampleTasks"`
+	HAAICategories  []string `json:"haaiCategories"` // category or domain IDs
+	Status          string   `json:"status"`
+}
+
+type AdditionalTestScenarios struct {
+	Description string               `json:"description"`
+	Scenarios   []ValidationScenario `json:"scenarios"`
+}
+
+type ValidationScenario struct {
+	Name             string `json:"name"`
+	Occupation       string `json:"occupation"`
+	SampleActivities []struct {
+		Activity   string `json:"activity"`
+		CategoryID string `json:"categoryId"`
+	} `json:"sampleActivities"`
+}
+
+type EdgeCaseTests struct {
+	Description string         `json:"description"`
+	TestCases   []EdgeCaseTest `json:"testCases"`
+}
+
+type EdgeCaseTest struct {
+	Activity   string   `json:"activity"`
+	Candidates []string `json:"candidates"` // category ID followed by its name
+	Resolution string   `json:"resolution"`
+	Rationale  string   `json:"rationale"`
+}
+
+type MECEComplianceCheck struct {
+	Description string      `json:"description"`
+	Checks      []MECECheck `json:"checks"`
+}
+
+type MECECheck struct {
+	CheckType string `json:"checkType"`
+	Method    string `json:"method"`
+	Status    string `json:"status"`
+	Notes     string `json:"notes"`
+}
+
+type ValidationProtocol struct {
+	InterRaterReliability InterRaterReliability `json:"interRaterReliability"`
+}
+
+type InterRaterReliability struct {
+	Metric    string  `json:"metric"`
+	Threshold float64 `json:"threshold"`
+	Procedure string  `json:"procedure"`
+	Status    string  `json:"status"`
+}
+
+// AI deployment events (deployments.json)
+type DeploymentsFile struct {
+	Version     string            `json:"version"`
+	Description string            `json:"description"`
+	Events      []DeploymentEvent `json:"events"`
+}
+
+// DeploymentEvent records when an AI system reached a capability level on
+// HAAI categories or activities. A category target stands for every activity
+// in the category.
+type DeploymentEvent struct {
+	Date       string   `json:"date"` // YYYY-MM-DD, YYYY-MM or YYYY
+	System     string   `json:"system"`
+	Targets    []string `json:"targets"` // category or activity IDs
+	Capability string   `json:"capability"`
+	Alignment  string   `json:"alignment,omitempty"` // system in mappings.aiCapabilityAlignment
+	Evidence   string   `json:"evidence,omitempty"`
+	Source     string   `json:"source,omitempty"`
+}
@@ -0,0 +1,373 @@
+package haai
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Validation check outcomes. Warnings are reported but do not fail a suite.
+const (
+	CheckPass = "pass"
+	CheckFail = "fail"
+	CheckWarn = "warn"
+)
+
+// ValidationCheck is the outcome of one test case.
+type ValidationCheck struct {
+	Case    string `json:"case"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// ValidationSuite is the outcome of one suite in validation.json. Name is the
+// suite's key in the file (e.g. "edgeCaseTests").
+type ValidationSuite struct {
+	Name     string            `json:"name"`
+	Passed   int               `json:"passed"`
+	Failed   int               `json:"failed"`
+	Warnings int               `json:"warnings"`
+	Checks   []ValidationCheck `json:"checks"`
+}
+
+// OK reports whether no check in the suite failed.
+func (s *ValidationSuite) OK() bool {
+	return s.Failed == 0
+}
+
+func (s *ValidationSuite) add(name, status, format string, args ...any) {
+	s.Checks = append(s.Checks, ValidationCheck{Case: name, Status: status, Message: fmt.Sprintf(format, args...)})
+	switch status {
+	case CheckPass:
+		s.Passed++
+	case CheckFail:
+		s.Failed++
+	case CheckWarn:
+		s.Warnings++
+	}
+}
+
+// check records a failure listing problems, or a pass if there are none
+func (s *ValidationSuite) check(name string, problems []string) {
+	if len(problems) > 0 {
+		s.add(name, CheckFail, "%s", strings.Join(problems, "; "))
+		return
+	}
+	s.add(name, CheckPass, "")
+}
+
+// ValidationReport is the result of running every suite in validation.json.
+// ClassifierCases counts the cases with a recorded category that were also
+// run through Classify; ClassifierAgreed those where its top choice matched.
+type ValidationReport struct {
+	Suites           []*ValidationSuite `json:"suites"`
+	Passed           int                `json:"passed"`
+	Failed           int                `json:"failed"`
+	Warnings         int                `json:"warnings"`
+	ClassifierCases  int                `json:"classifierCases"`
+	ClassifierAgreed int                `json:"classifierAgreed"`
+}
+
+// OK reports whether every suite passed.
+func (r *ValidationReport) OK() bool {
+	return r.Failed == 0
+}
+
+// Suite returns the suite with the given name, or nil.
+func (r *ValidationReport) Suite(name string) *ValidationSuite {
+	for _, s := range r.Suites {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// ValidationFile returns the parsed validation.json.
+func (ds *Dataset) ValidationFile() (*ValidationFile, error) {
+	var vf ValidationFile
+	if err := ds.loadJSON("validation.json", &vf); err != nil {
+		return nil, err
+	}
+	return &vf, nil
+}
+
+// Validate runs the suites in validation.json against the loaded taxonomy:
+// every referenced category or domain must exist and contain activities,
+// recorded category names must match the taxonomy, edge-case resolutions
+// must be among their candidates, and the MECE checks are recomputed from
+// the activities. Recorded classifications are also compared against
+// Classify; a disagreement is a warning, since the classifier is lexical.
+func (ds *Dataset) Validate() (*ValidationReport, error) {
+	vf, err := ds.ValidationFile()
+	if err != nil {
+		return nil, err
+	}
+	v := &validator{ds: ds, report: &ValidationReport{}, counts: make(map[string]int)}
+	for _, a := range ds.activities {
+		v.counts[a.CategoryID]++
+		v.counts[fmt.Sprint(DomainFromID(a.ID))]++
+	}
+
+	v.dayInLife(vf.DayInLifeCoverageTest)
+	v.occupations(vf.OccupationCoverageTest)
+	v.scenarios(vf.AdditionalTestScenarios)
+	v.edgeCases(vf.EdgeCaseTests)
+	v.mece(vf.MECEComplianceCheck)
+
+	for _, s := range v.report.Suites {
+		v.report.Passed += s.Passed
+		v.report.Failed += s.Failed
+		v.report.Warnings += s.Warnings
+	}
+	return v.report, nil
+}
+
+// RecordValidation writes date (YYYY-MM-DD) as lastCompleted on every suite
+// in validation.json that passed in r, and on validationProtocol.coverageAudit
+// when all coverage suites passed. Failed suites keep their previous date.
+func (ds *Dataset) RecordValidation(r *ValidationReport, date string) error {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("invalid date %q (want YYYY-MM-DD)", date)
+	}
+	root, err := ds.readOrdered("validation.json")
+	if err != nil {
+		return err
+	}
+	for _, s := range r.Suites {
+		if obj := root.Object(s.Name); obj != nil && s.OK() {
+			obj.Set("lastCompleted", date)
+		}
+	}
+	covered := true
+	for _, name := range coverageSuites {
+		if s := r.Suite(name); s == nil || !s.OK() {
+			covered = false
+		}
+	}
+	if audit := root.Object("validationProtocol").Object("coverageAudit"); audit != nil && covered {
+		audit.Set("lastCompleted", date)
+	}
+
+	data, err := encodeOrdered(root)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(ds.dir, "validation.json"), data)
+}
+
+// coverageSuites are the suites that together make up a coverage audit
+var coverageSuites = []string{"dayInLifeCoverageTest", "occupationCoverageTest", "additionalTestScenarios"}
+
+type validator struct {
+	ds     *Dataset
+	report *ValidationReport
+	counts map[string]int // activities per category ID and per domain ID
+}
+
+func (v *validator) suite(name string) *ValidationSuite {
+	s := &ValidationSuite{Name: name}
+	v.report.Suites = append(v.report.Suites, s)
+	return s
+}
+
+// categoryProblems checks that a category exists, has activities and, if
+// name is given, is called name
+func (v *validator) categoryProblems(id, name string) []string {
+	cat, ok := v.ds.Category(id)
+	if !ok {
+		return []string{fmt.Sprintf("category %s does not exist", id)}
+	}
+	var problems []string
+	if name != "" && !strings.EqualFold(strings.TrimSpace(name), cat.Name) {
+		problems = append(problems, fmt.Sprintf("category %s is %q, not %q", id, cat.Name, name))
+	}
+	if v.counts[id] == 0 {
+		problems = append(problems, fmt.Sprintf("category %s has no activities", id))
+	}
+	return problems
+}
+
+// compare classifies text and records a warning unless its top category is
+// one of want. If among is not empty, only those categories are considered.
+func (v *validator) compare(s *ValidationSuite, name, text string, want, among []string) {
+	c := v.ds.Classify(text, 0)
+	v.report.ClassifierCases++
+	for i, cand := range c.Candidates {
+		if len(among) > 0 && !containsString(among, cand.Category.ID) {
+			continue
+		}
+		if containsString(want, cand.Category.ID) {
+			v.report.ClassifierAgreed++
+			return
+		}
+		rank := "not ranked"
+		for j, other := range c.Candidates {
+			if containsString(want, other.Category.ID) {
+				rank = fmt.Sprintf("#%d", j+1)
+				break
+			}
+		}
+		s.add(name, CheckWarn, "classifier prefers %s %s (#%d); %s is %s",
+			cand.Category.ID, cand.Category.Name, i+1, strings.Join(want, "/"), rank)
+		return
+	}
+	s.add(name, CheckWarn, "classifier finds no matching category")
+}
+
+func (v *validator) dayInLife(t DayInLifeCoverageTest) {
+	s := v.suite("dayInLifeCoverageTest")
+	for _, e := range t.Activities {
+		name := strings.TrimSpace(e.Time + " " + e.Activity)
+		problems := v.categoryProblems(e.Classification.CategoryID, e.Classification.CategoryName)
+		s.check(name, problems)
+		if len(problems) == 0 {
+			v.compare(s, name, e.Activity, []string{e.Classification.CategoryID}, nil)
+		}
+	}
+}
+
+func (v *validator) occupations(t OccupationCoverageTest) {
+	s := v.suite("occupationCoverageTest")
+	seen := make(map[int]bool)
+	for _, tc := range t.TestCases {
+		name := fmt.Sprintf("ISCO %d %s", tc.ISCOGroup, tc.OccupationGroup)
+		var problems []string
+		if seen[tc.ISCOGroup] {
+			problems = append(problems, fmt.Sprintf("ISCO group %d is listed more than once", tc.ISCOGroup))
+		}
+		seen[tc.ISCOGroup] = true
+		if len(tc.HAAICategories) == 0 {
+			problems = append(problems, "no HAAI categories listed")
+		}
+		for _, ref := range tc.HAAICategories {
+			if !strings.Contains(ref, ".") {
+				if _, ok := v.ds.Domain(DomainFromID(ref)); !ok {
+					problems = append(problems, fmt.Sprintf("domain %s does not exist", ref))
+				} else if v.counts[ref] == 0 {
+					problems = append(problems, fmt.Sprintf("domain %s has no activities", ref))
+				}
+				continue
+			}
+			problems = append(problems, v.categoryProblems(ref, "")...)
+		}
+		s.check(name, problems)
+	}
+}
+
+func (v *validator) scenarios(t AdditionalTestScenarios) {
+	s := v.suite("additionalTestScenarios")
+	for _, sc := range t.Scenarios {
+		for _, a := range sc.SampleActivities {
+			name := sc.Name + ": " + a.Activity
+			problems := v.categoryProblems(a.CategoryID, "")
+			s.check(name, problems)
+			if len(problems) == 0 {
+				v.compare(s, name, a.Activity, []string{a.CategoryID}, nil)
+			}
+		}
+	}
+}
+
+var categoryRef = regexp.MustCompile(`\b\d+\.\d+\b`)
+
+func (v *validator) edgeCases(t EdgeCaseTests) {
+	s := v.suite("edgeCaseTests")
+	for _, tc := range t.TestCases {
+		var problems, candidates []string
+		for _, c := range tc.Candidates {
+			id, name, _ := strings.Cut(strings.TrimSpace(c), " ")
+			candidates = append(candidates, id)
+			problems = append(problems, v.categoryProblems(id, name)...)
+		}
+
+		// A resolution such as "Context-dependent" names no category; the
+		// rationale then says which categories the contexts lead to
+		resolved := categoryRef.FindAllString(tc.Resolution, -1)
+		if len(resolved) == 0 {
+			resolved = categoryRef.FindAllString(tc.Rationale, -1)
+		}
+		if len(resolved) == 0 {
+			problems = append(problems, fmt.Sprintf("resolution %q names no category", tc.Resolution))
+		}
+		for _, id := range resolved {
+			if !containsString(candidates, id) {
+				problems = append(problems, fmt.Sprintf("resolution %s is not one of the candidates", id))
+			}
+		}
+		s.check(tc.Activity, problems)
+		if len(problems) == 0 {
+			v.compare(s, tc.Activity, tc.Activity, resolved, candidates)
+		}
+	}
+}
+
+func (v *validator) mece(t MECEComplianceCheck) {
+	s := v.suite("meceComplianceCheck")
+	for _, c := range t.Checks {
+		switch strings.ToLower(c.CheckType) {
+		case "mutual exclusivity":
+			s.check(c.CheckType, v.exclusivityProblems())
+		case "collective exhaustiveness":
+			s.check(c.CheckType, v.exhaustivenessProblems())
+		case "no orphan activities":
+			var problems []string
+			for _, a := range v.ds.activities {
+				if _, ok := v.ds.Category(a.CategoryID); !ok {
+					problems = append(problems, fmt.Sprintf("activity %s is in unknown category %q", a.ID, a.CategoryID))
+				}
+			}
+			s.check(c.CheckType, problems)
+		default:
+			s.add(c.CheckType, CheckWarn, "no executable check for this check type")
+		}
+	}
+}
+
+// exclusivityProblems checks that every activity sits in exactly one category
+// and that boundary rules name existing domains
+func (v *validator) exclusivityProblems() []string {
+	var problems []string
+	seen := make(map[string]bool)
+	for _, a := range v.ds.activities {
+		if seen[a.ID] {
+			problems = append(problems, fmt.Sprintf("activity %s is listed more than once", a.ID))
+		}
+		seen[a.ID] = true
+		if i := strings.LastIndex(a.ID, "."); i < 0 || a.ID[:i] != a.CategoryID {
+			problems = append(problems, fmt.Sprintf("activity %s has categoryId %s", a.ID, a.CategoryID))
+		}
+	}
+	for _, r := range v.ds.taxonomy.ClassificationRules.BoundaryRules {
+		a, b, ok := r.Domains()
+		if !ok {
+			problems = append(problems, fmt.Sprintf("boundary %q does not name two domains", r.Boundary))
+			continue
+		}
+		for _, d := range []int{a, b} {
+			if _, ok := v.ds.Domain(d); !ok {
+				problems = append(problems, fmt.Sprintf("boundary %q refers to unknown domain %d", r.Boundary, d))
+			}
+		}
+	}
+	return problems
+}
+
+// exhaustivenessProblems checks that every category has activities and that
+// the coverage suites run so far found no gaps
+func (v *validator) exhaustivenessProblems() []string {
+	var problems []string
+	for _, c := range v.ds.Categories() {
+		if v.counts[c.ID] == 0 {
+			problems = append(problems, fmt.Sprintf("category %s has no activities", c.ID))
+		}
+	}
+	for _, name := range coverageSuites {
+		if s := v.report.Suite(name); s != nil && !s.OK() {
+			problems = append(problems, fmt.Sprintf("%s has %d failing cases", name, s.Failed))
+		}
+	}
+	return problems
+}
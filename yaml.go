@@ -0,0 +1,122 @@
+package haai
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// MarshalYAML renders v as YAML. Field names and order follow v's JSON
+// encoding, so the output matches what json.Marshal produces.
+func MarshalYAML(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	doc, err := decodeOrdered(dec, data)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	switch x := doc.(type) {
+	case *orderedObject, *orderedArray:
+		writeYAML(&buf, x, 0)
+	default:
+		writeYAMLScalar(&buf, x)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// writeYAML writes a block collection at the given indentation depth
+func writeYAML(buf *bytes.Buffer, v any, depth int) {
+	indent := strings.Repeat("  ", depth)
+	switch x := v.(type) {
+	case *orderedObject:
+		for i, k := range x.keys {
+			// the first key of a list item follows its "- " marker
+			if i > 0 || !bytes.HasSuffix(buf.Bytes(), []byte("- ")) {
+				buf.WriteString(indent)
+			}
+			writeYAMLScalar(buf, k)
+			buf.WriteByte(':')
+			writeYAMLValue(buf, x.values[k], depth+1)
+		}
+	case *orderedArray:
+		for _, item := range x.items {
+			if obj, ok := item.(*orderedObject); ok && len(obj.keys) > 0 {
+				buf.WriteString(indent + "- ")
+				writeYAML(buf, obj, depth+1)
+				continue
+			}
+			buf.WriteString(indent + "-")
+			writeYAMLValue(buf, item, depth+1)
+		}
+	}
+}
+
+// writeYAMLValue writes the value after a "key:" or "-" marker
+func writeYAMLValue(buf *bytes.Buffer, v any, depth int) {
+	switch x := v.(type) {
+	case *orderedObject:
+		if len(x.keys) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		buf.WriteByte('\n')
+		writeYAML(buf, x, depth)
+	case *orderedArray:
+		if len(x.items) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteByte('\n')
+		writeYAML(buf, x, depth)
+	default:
+		buf.WriteByte(' ')
+		writeYAMLScalar(buf, x)
+		buf.WriteByte('\n')
+	}
+}
+
+func writeYAMLScalar(buf *bytes.Buffer, v any) {
+	switch x := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case string:
+		if yamlPlain(x) {
+			buf.WriteString(x)
+			return
+		}
+		// JSON string syntax is valid double-quoted YAML
+		writeScalar(buf, x)
+	default:
+		writeScalar(buf, x)
+	}
+}
+
+// yamlPlain reports whether s can be written unquoted without being read
+// back as another type or breaking the syntax
+func yamlPlain(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return false
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", "y", "n":
+		return false
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`0123456789.+") {
+		return false
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}